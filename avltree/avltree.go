@@ -8,9 +8,11 @@ package avltree
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"iter"
 	"maps"
+	"slices"
 	"strings"
 
 	"github.com/qntx/gods/cmp"
@@ -80,9 +82,13 @@ var _ container.OrderedMap[int, int] = (*Tree[int, int])(nil)
 // K must be comparable and compatible with the provided comparator.
 // V can be any type.
 type Tree[K comparable, V any] struct {
-	root *Node[K, V]       // Root node of the tree
-	len  int               // Number of nodes in the tree
-	cmp  cmp.Comparator[K] // Comparator for ordering keys
+	root  *Node[K, V]       // Root node of the tree
+	begin *Node[K, V]       // Leftmost node (minimum key), cached
+	end   *Node[K, V]       // Rightmost node (maximum key), cached
+	len   int               // Number of nodes in the tree
+	cmp   cmp.Comparator[K] // Comparator for ordering keys
+
+	rotations *[]string // Rotation log collected by DeleteTraced, nil otherwise
 }
 
 // New creates a new AVL tree with a default comparator for ordered types.
@@ -92,6 +98,15 @@ func New[K cmp.Ordered, V any]() *Tree[K, V] {
 	return &Tree[K, V]{cmp: cmp.Compare[K]}
 }
 
+// NewReverse creates a new AVL tree ordering keys from largest to smallest.
+//
+// Begin, Floor, Ceiling and iteration all follow the reversed order, so Begin
+// returns the largest key and Floor(k) the smallest key >= k.
+// Time complexity: O(1).
+func NewReverse[K cmp.Ordered, V any]() *Tree[K, V] {
+	return &Tree[K, V]{cmp: func(a, b K) int { return cmp.Compare(b, a) }}
+}
+
 // NewWith creates a new AVL tree with a custom comparator.
 //
 // The comparator defines the key ordering. Time complexity: O(1).
@@ -105,48 +120,77 @@ func NewWith[K comparable, V any](cmp cmp.Comparator[K]) *Tree[K, V] {
 // Panics if the key type is incompatible with the comparator.
 // Time complexity: O(log n).
 func (t *Tree[K, V]) Put(key K, val V) {
-	if t.root == nil {
-		t.root = &Node[K, V]{key: key, value: val}
-		t.len++
+	node, parent, cmp := t.locate(key)
+	if node != nil {
+		node.value = val
 
 		return
 	}
 
-	node, parent := t.root, (*Node[K, V])(nil)
+	t.attach(parent, cmp, key, val)
+}
 
-	var cmp int
+// GetOrCompute returns the value stored for key and true if it is present.
+// Otherwise it calls compute, inserts the result under key, and returns it
+// with false.
+//
+// compute is called at most once, and only on a miss, so an expensive default
+// is never built needlessly. The tree is descended only once; compute must not
+// modify the tree.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) GetOrCompute(key K, compute func() V) (value V, loaded bool) {
+	node, parent, cmp := t.locate(key)
+	if node != nil {
+		return node.value, true
+	}
 
-	for node != nil {
-		parent = node
-		cmp = t.cmp(key, node.key)
+	value = compute()
+	t.attach(parent, cmp, key, value)
 
-		switch {
-		case cmp < 0:
-			node = node.left
-		case cmp > 0:
-			node = node.right
-		default: // cmp == 0
-			node.value = val
+	return value, false
+}
 
-			return
-		}
+// PutAll inserts or updates every key-value pair from the given map.
+//
+// The keys are sorted by the tree's comparator before insertion, so the result
+// does not depend on Go's randomized map iteration order.
+// Returns the number of newly inserted keys (overwritten keys are not counted).
+// Time complexity: O(k log k + k log n) for k entries.
+func (t *Tree[K, V]) PutAll(entries map[K]V) int {
+	keys := slices.Collect(maps.Keys(entries))
+	slices.SortFunc(keys, t.cmp)
+
+	prev := t.len
+	for _, k := range keys {
+		t.Put(k, entries[k])
 	}
 
-	n := &Node[K, V]{key: key, value: val, parent: parent}
-	if cmp < 0 {
-		parent.left = n
-	} else {
-		parent.right = n
+	return t.len - prev
+}
+
+// PutPairs inserts or updates the pairs (keys[i], values[i]) in the given order,
+// so a later duplicate key overwrites an earlier one.
+//
+// Returns the number of newly inserted keys (overwritten keys are not counted).
+// Panics if keys and values have different lengths.
+// Time complexity: O(k log n) for k pairs.
+func (t *Tree[K, V]) PutPairs(keys []K, values []V) int {
+	if len(keys) != len(values) {
+		panic("avltree: keys and values must have the same length")
 	}
 
-	t.len++
+	prev := t.len
+	for i, k := range keys {
+		t.Put(k, values[i])
+	}
 
-	t.insertFixup(parent)
+	return t.len - prev
 }
 
 // Delete removes the node with the specified key from the tree.
 //
-// Returns true if a node was removed, false if the key was not found.
+// Returns the removed value and true if a node was removed, or the zero value
+// and false if the key was not found.
 // Panics if the key type is incompatible with the comparator.
 // Time complexity: O(log n).
 func (t *Tree[K, V]) Delete(key K) (value V, found bool) {
@@ -155,6 +199,8 @@ func (t *Tree[K, V]) Delete(key K) (value V, found bool) {
 		return value, false
 	}
 
+	value = node.value // Capture before a successor's data may overwrite it.
+
 	var fixupStartNode *Node[K, V]
 
 	if node.left != nil && node.right != nil {
@@ -174,6 +220,16 @@ func (t *Tree[K, V]) Delete(key K) (value V, found bool) {
 		child = node.right
 	}
 
+	// Rotations move nodes but not their data, so the cached minimum and
+	// maximum only go stale when one of those nodes is unlinked.
+	if node == t.begin {
+		t.begin = t.next(node)
+	}
+
+	if node == t.end {
+		t.end = t.prev(node)
+	}
+
 	t.replaceNode(node, child)
 
 	t.len--
@@ -182,7 +238,36 @@ func (t *Tree[K, V]) Delete(key K) (value V, found bool) {
 		t.deleteFixup(fixupStartNode)
 	}
 
-	return node.value, true
+	return value, true
+}
+
+// DeleteTraced removes the node with the specified key like Delete, and also
+// returns a log of the rotations performed while rebalancing, in the order
+// they happened, e.g. ["rotateLeft at 2", "rotateRight at 5"]. Each entry
+// names the rotation and the key of its pivot node.
+//
+// Intended for debugging and teaching; Delete is unaffected.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) DeleteTraced(key K) (removed bool, rotations []string) {
+	t.rotations = &rotations
+	_, removed = t.Delete(key)
+	t.rotations = nil
+
+	return removed, rotations
+}
+
+// DeleteAll removes every key in keys that is present in the tree.
+//
+// Keys that are absent, or repeated, are skipped without error.
+// Returns the number of keys removed.
+// Time complexity: O(k log n) for k keys.
+func (t *Tree[K, V]) DeleteAll(keys []K) int {
+	prev := t.len
+	for _, k := range keys {
+		t.Delete(k)
+	}
+
+	return prev - t.len
 }
 
 // Get retrieves the value associated with the specified key.
@@ -219,21 +304,21 @@ func (t *Tree[K, V]) Has(key K) bool {
 }
 
 // GetBeginNode returns the leftmost node (minimum key), or nil if the tree is empty.
-// Time complexity: O(log n).
+// Time complexity: O(1).
 func (t *Tree[K, V]) GetBeginNode() *Node[K, V] {
-	return t.getLeftNode(t.root)
+	return t.begin
 }
 
 // GetEndNode returns the rightmost node (maximum key), or nil if the tree is empty.
-// Time complexity: O(log n).
+// Time complexity: O(1).
 func (t *Tree[K, V]) GetEndNode() *Node[K, V] {
-	return t.getRightNode(t.root)
+	return t.end
 }
 
 // Begin returns the minimum key and value in the tree.
 //
 // Returns found as true if an element is found, false otherwise.
-// Time complexity: O(log n).
+// Time complexity: O(1).
 func (t *Tree[K, V]) Begin() (key K, value V, found bool) {
 	node := t.GetBeginNode()
 	if node != nil {
@@ -250,7 +335,7 @@ func (t *Tree[K, V]) Begin() (key K, value V, found bool) {
 // End returns the maximum key and value in the tree.
 //
 // Returns found as true if an element is found, false otherwise.
-// Time complexity: O(log n).
+// Time complexity: O(1).
 func (t *Tree[K, V]) End() (key K, value V, found bool) {
 	node := t.GetEndNode()
 	if node != nil {
@@ -304,6 +389,43 @@ func (t *Tree[K, V]) DeleteEnd() (key K, value V, removed bool) {
 	return zeroKey, zeroValue, false
 }
 
+// Trim removes every entry whose key is less than low or greater than high,
+// keeping only the keys in [low, high], and returns the number removed.
+// If low is greater than high, the tree is emptied.
+//
+// Entries are removed one by one from either end, so the tree stays balanced
+// throughout.
+// Time complexity: O(k log n) for k removed entries.
+func (t *Tree[K, V]) Trim(low, high K) int {
+	prev := t.len
+
+	for t.begin != nil && t.cmp(t.begin.key, low) < 0 {
+		t.Delete(t.begin.key)
+	}
+
+	for t.end != nil && t.cmp(t.end.key, high) > 0 {
+		t.Delete(t.end.key)
+	}
+
+	return prev - t.len
+}
+
+// PopMin removes and returns the minimum key-value pair.
+// Returns the removed key, value, and true, or zero values and false if the
+// tree is empty. Alias of DeleteBegin, named consistently across the tree packages.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) PopMin() (key K, value V, found bool) {
+	return t.DeleteBegin()
+}
+
+// PopMax removes and returns the maximum key-value pair.
+// Returns the removed key, value, and true, or zero values and false if the
+// tree is empty. Alias of DeleteEnd, named consistently across the tree packages.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) PopMax() (key K, value V, found bool) {
+	return t.DeleteEnd()
+}
+
 // Floor finds the largest node with a key less than or equal to the given key.
 //
 // Returns the node and true if found, or nil and false if not.
@@ -328,6 +450,34 @@ func (t *Tree[K, V]) Floor(key K) (*Node[K, V], bool) {
 	return floor, floor != nil
 }
 
+// GetNodeOrFloor finds the node with the specified key, or the floor node if the
+// key is absent.
+//
+// Returns the node and true on an exact match. Otherwise returns the largest node
+// with a key less than the given key and false, or nil and false if no such node
+// exists. Combines lookup and Floor in a single descent, which is handy for
+// interval maps keyed by start points.
+// Panics if the key type is incompatible with the comparator.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) GetNodeOrFloor(key K) (*Node[K, V], bool) {
+	var floor *Node[K, V]
+
+	node := t.root
+	for node != nil {
+		switch cmp := t.cmp(key, node.key); {
+		case cmp == 0:
+			return node, true
+		case cmp > 0:
+			floor = node
+			node = node.right
+		default:
+			node = node.left
+		}
+	}
+
+	return floor, false
+}
+
 // Ceiling finds the smallest node with a key greater than or equal to the given key.
 //
 // Returns the node and true if found, or nil and false if not.
@@ -352,6 +502,57 @@ func (t *Tree[K, V]) Ceiling(key K) (*Node[K, V], bool) {
 	return ceil, ceil != nil
 }
 
+// FloorEntry returns the largest key less than or equal to the given key,
+// along with its value.
+//
+// Returns found as true if such a key exists, false otherwise.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) FloorEntry(key K) (floorKey K, value V, found bool) {
+	node, _ := t.Floor(key)
+	if node != nil {
+		return node.key, node.value, true
+	}
+
+	return floorKey, value, false
+}
+
+// CeilingEntry returns the smallest key greater than or equal to the given
+// key, along with its value.
+//
+// Returns found as true if such a key exists, false otherwise.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) CeilingEntry(key K) (ceilingKey K, value V, found bool) {
+	node, _ := t.Ceiling(key)
+	if node != nil {
+		return node.key, node.value, true
+	}
+
+	return ceilingKey, value, false
+}
+
+// Nearest returns the entry whose key is closest to the given key, as measured
+// by dist, choosing between the floor and the ceiling of key. Ties are broken
+// toward the floor. dist should return a non-negative distance between keys
+// (e.g. the absolute difference for numbers).
+//
+// Returns found as false if the tree is empty.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) Nearest(key K, dist func(a, b K) int) (nearestKey K, value V, found bool) {
+	floor, _ := t.Floor(key)
+	ceil, _ := t.Ceiling(key)
+
+	switch {
+	case floor == nil && ceil == nil:
+		return nearestKey, value, false
+	case floor == nil:
+		return ceil.key, ceil.value, true
+	case ceil == nil || dist(key, floor.key) <= dist(key, ceil.key):
+		return floor.key, floor.value, true
+	default:
+		return ceil.key, ceil.value, true
+	}
+}
+
 // Keys returns all keys in in-order sequence.
 // Time complexity: O(n).
 func (t *Tree[K, V]) Keys() []K {
@@ -412,6 +613,7 @@ func (t *Tree[K, V]) IsEmpty() bool {
 // Time complexity: O(1).
 func (t *Tree[K, V]) Clear() {
 	t.root = nil
+	t.begin, t.end = nil, nil
 	t.len = 0
 }
 
@@ -430,6 +632,8 @@ func (t *Tree[K, V]) Clone() container.Map[K, V] {
 	}
 
 	newTree.root = cloneNode(t.root, nil)
+	newTree.begin = newTree.getLeftNode(newTree.root)
+	newTree.end = newTree.getRightNode(newTree.root)
 
 	return newTree
 }
@@ -441,21 +645,10 @@ func (t *Tree[K, V]) Clone() container.Map[K, V] {
 // steps are amortized O(1), with overall iteration complexity of O(n).
 func (t *Tree[K, V]) Iter() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		node := t.GetBeginNode()
-		for node != nil {
+		for node := t.GetBeginNode(); node != nil; node = t.next(node) {
 			if !yield(node.key, node.value) {
 				return
 			}
-
-			if node.right != nil {
-				node = t.getLeftNode(node.right)
-			} else {
-				for node.parent != nil && node == node.parent.right {
-					node = node.parent
-				}
-
-				node = node.parent
-			}
 		}
 	}
 }
@@ -467,25 +660,190 @@ func (t *Tree[K, V]) Iter() iter.Seq2[K, V] {
 // steps are amortized O(1), with overall iteration complexity of O(n).
 func (t *Tree[K, V]) RIter() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		node := t.GetEndNode()
-		for node != nil {
+		for node := t.GetEndNode(); node != nil; node = t.prev(node) {
 			if !yield(node.key, node.value) {
 				return
 			}
+		}
+	}
+}
 
-			if node.left != nil {
-				node = t.getRightNode(node.left)
-			} else {
-				for node.parent != nil && node == node.parent.left {
-					node = node.parent
-				}
+// Range returns an iterator over the key-value pairs with low <= key <= high,
+// in ascending order. The bounds need not be present in the tree. Yields
+// nothing if low > high or the tree is empty.
+//
+// Starts from the ceiling of low and steps via parent pointers, so a full
+// traversal costs O(log n + k) for k yielded pairs.
+func (t *Tree[K, V]) Range(low, high K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if t.cmp(low, high) > 0 {
+			return
+		}
 
-				node = node.parent
+		node, _ := t.Ceiling(low)
+		for ; node != nil && t.cmp(node.key, high) <= 0; node = t.next(node) {
+			if !yield(node.key, node.value) {
+				return
 			}
 		}
 	}
 }
 
+// RangeReverse returns an iterator over the key-value pairs with
+// low <= key <= high, in descending order. The bounds need not be present in
+// the tree. Yields nothing if low > high or the tree is empty.
+//
+// Starts from the floor of high and steps via parent pointers, so a full
+// traversal costs O(log n + k) for k yielded pairs.
+func (t *Tree[K, V]) RangeReverse(low, high K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if t.cmp(low, high) > 0 {
+			return
+		}
+
+		node, _ := t.Floor(high)
+		for ; node != nil && t.cmp(node.key, low) >= 0; node = t.prev(node) {
+			if !yield(node.key, node.value) {
+				return
+			}
+		}
+	}
+}
+
+var _ container.ReverseIteratorWithKey[string, int] = (*Iterator[string, int])(nil)
+
+// position records where an Iterator sits relative to the tree's elements.
+type position byte
+
+const (
+	begin   position = iota // Before the first element.
+	between                 // On an element.
+	end                     // Past the last element.
+)
+
+// Iterator is a stateful, bidirectional iterator over the tree's key-value
+// pairs in sorted order, satisfying container.ReverseIteratorWithKey.
+//
+// Iter corresponds to Iterator followed by repeated Next calls, and RIter to
+// ReverseIterator followed by repeated Prev calls. Unlike those closures, an
+// Iterator can be paused, moved in either direction and repositioned. It is
+// invalidated by any modification of the tree other than through its own
+// methods.
+type Iterator[K comparable, V any] struct {
+	tree     *Tree[K, V]
+	node     *Node[K, V]
+	position position
+}
+
+// Iterator returns a stateful iterator positioned before the first element.
+// Time complexity: O(1).
+func (t *Tree[K, V]) Iterator() *Iterator[K, V] {
+	return &Iterator[K, V]{tree: t, position: begin}
+}
+
+// ReverseIterator returns a stateful iterator positioned past the last element,
+// ready for backward traversal with Prev.
+// Time complexity: O(1).
+func (t *Tree[K, V]) ReverseIterator() *Iterator[K, V] {
+	return &Iterator[K, V]{tree: t, position: end}
+}
+
+// Next moves the iterator to the next element and returns true if there was one.
+// Time complexity: O(log n) worst case, amortized O(1).
+func (it *Iterator[K, V]) Next() bool {
+	switch it.position {
+	case end:
+		return false
+	case begin:
+		it.node = it.tree.GetBeginNode()
+	default:
+		it.node = it.tree.next(it.node)
+	}
+
+	if it.node == nil {
+		it.position = end
+
+		return false
+	}
+
+	it.position = between
+
+	return true
+}
+
+// Prev moves the iterator to the previous element and returns true if there was one.
+// Time complexity: O(log n) worst case, amortized O(1).
+func (it *Iterator[K, V]) Prev() bool {
+	switch it.position {
+	case begin:
+		return false
+	case end:
+		it.node = it.tree.GetEndNode()
+	default:
+		it.node = it.tree.prev(it.node)
+	}
+
+	if it.node == nil {
+		it.position = begin
+
+		return false
+	}
+
+	it.position = between
+
+	return true
+}
+
+// Key returns the key of the current element.
+// Only valid after Next, Prev, First or Last returned true.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Key() K {
+	return it.node.key
+}
+
+// Value returns the value of the current element.
+// Only valid after Next, Prev, First or Last returned true.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Value() V {
+	return it.node.value
+}
+
+// Node returns the current node, or nil if the iterator is not on an element.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Node() *Node[K, V] {
+	return it.node
+}
+
+// Begin resets the iterator to before the first element.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Begin() {
+	it.node = nil
+	it.position = begin
+}
+
+// End moves the iterator past the last element.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) End() {
+	it.node = nil
+	it.position = end
+}
+
+// First moves the iterator to the first element and returns true if there was one.
+// Time complexity: O(log n).
+func (it *Iterator[K, V]) First() bool {
+	it.Begin()
+
+	return it.Next()
+}
+
+// Last moves the iterator to the last element and returns true if there was one.
+// Time complexity: O(log n).
+func (it *Iterator[K, V]) Last() bool {
+	it.End()
+
+	return it.Prev()
+}
+
 var _ json.Marshaler = (*Tree[string, int])(nil)
 var _ json.Unmarshaler = (*Tree[string, int])(nil)
 
@@ -514,6 +872,110 @@ func (tree *Tree[K, V]) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ErrInvalidShape is returned by UnmarshalShape when the input does not
+// describe a valid AVL tree for the receiver's comparator.
+var ErrInvalidShape = errors.New("avltree: invalid tree shape")
+
+// shapeNode is the serialized form of a node used by MarshalShape.
+type shapeNode[K comparable, V any] struct {
+	Key     K                `json:"k"`
+	Value   V                `json:"v"`
+	Balance int              `json:"b"`
+	Left    *shapeNode[K, V] `json:"l,omitempty"`
+	Right   *shapeNode[K, V] `json:"r,omitempty"`
+}
+
+// MarshalShape outputs a JSON representation of the tree that preserves its
+// structure: each node is an object with its key ("k"), value ("v"), balance
+// factor ("b") and, when present, left ("l") and right ("r") subtrees. An
+// empty tree is encoded as null.
+//
+// Unlike MarshalJSON, the output can be loaded with UnmarshalShape without
+// re-inserting and rebalancing.
+// Time complexity: O(n).
+func (t *Tree[K, V]) MarshalShape() ([]byte, error) {
+	return json.Marshal(toShape(t.root))
+}
+
+// UnmarshalShape replaces the contents of the tree with the structure
+// produced by MarshalShape, rebuilding the nodes as they were instead of
+// re-inserting them.
+//
+// The input is validated against the tree's comparator: keys must be in
+// strictly increasing in-order sequence and every balance factor must match
+// the subtree heights and lie in [-1, 1]. On failure it returns an error
+// wrapping ErrInvalidShape and leaves the tree unchanged.
+// Time complexity: O(n).
+func (t *Tree[K, V]) UnmarshalShape(data []byte) error {
+	var shape *shapeNode[K, V]
+	if err := json.Unmarshal(data, &shape); err != nil {
+		return err
+	}
+
+	var prev *Node[K, V]
+
+	root, _, n, err := t.fromShape(shape, nil, &prev)
+	if err != nil {
+		return err
+	}
+
+	t.root, t.len = root, n
+	t.begin = t.getLeftNode(root)
+	t.end = t.getRightNode(root)
+
+	return nil
+}
+
+// toShape converts the subtree rooted at n to its serialized form.
+func toShape[K comparable, V any](n *Node[K, V]) *shapeNode[K, V] {
+	if n == nil {
+		return nil
+	}
+
+	return &shapeNode[K, V]{
+		Key:     n.key,
+		Value:   n.value,
+		Balance: n.b,
+		Left:    toShape(n.left),
+		Right:   toShape(n.right),
+	}
+}
+
+// fromShape rebuilds the subtree described by s in order, checking each key
+// against the previously built node prev. It returns the subtree root, its
+// height (-1 if empty) and its number of nodes.
+func (t *Tree[K, V]) fromShape(s *shapeNode[K, V], parent *Node[K, V], prev **Node[K, V]) (*Node[K, V], int, int, error) {
+	if s == nil {
+		return nil, -1, 0, nil
+	}
+
+	n := &Node[K, V]{key: s.Key, value: s.Value, b: s.Balance, parent: parent}
+
+	left, lh, ln, err := t.fromShape(s.Left, n, prev)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if *prev != nil && t.cmp((*prev).key, n.key) >= 0 {
+		return nil, 0, 0, fmt.Errorf("%w: key %v out of order after %v", ErrInvalidShape, n.key, (*prev).key)
+	}
+
+	*prev = n
+
+	right, rh, rn, err := t.fromShape(s.Right, n, prev)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if b := rh - lh; b != n.b || b < -1 || b > 1 {
+		return nil, 0, 0, fmt.Errorf("%w: node %v has balance %d, subtree heights give %d", ErrInvalidShape, n.key, n.b, b)
+	}
+
+	n.left, n.right = left, right
+
+	return n, 1 + max(lh, rh), 1 + ln + rn, nil
+}
+
 // String returns a string representation of the tree.
 // Time complexity: O(n).
 func (t *Tree[K, V]) String() string {
@@ -535,6 +997,57 @@ func (t *Tree[K, V]) Comparator() cmp.Comparator[K] {
 	return t.cmp
 }
 
+// locate descends the tree looking for key. It returns the node holding key,
+// or nil together with the parent a new node for key would be attached to and
+// the comparison of key with that parent. parent is nil if the tree is empty.
+func (t *Tree[K, V]) locate(key K) (node, parent *Node[K, V], cmp int) {
+	for node = t.root; node != nil; {
+		cmp = t.cmp(key, node.key)
+
+		switch {
+		case cmp < 0:
+			parent, node = node, node.left
+		case cmp > 0:
+			parent, node = node, node.right
+		default:
+			return node, parent, 0
+		}
+	}
+
+	return nil, parent, cmp
+}
+
+// attach inserts a new node for key below parent, on the side given by cmp,
+// as found by locate, and rebalances the tree.
+func (t *Tree[K, V]) attach(parent *Node[K, V], cmp int, key K, val V) {
+	n := &Node[K, V]{key: key, value: val, parent: parent}
+	t.len++
+
+	if parent == nil {
+		t.root = n
+		t.begin, t.end = n, n
+
+		return
+	}
+
+	if cmp < 0 {
+		parent.left = n
+	} else {
+		parent.right = n
+	}
+
+	// A new minimum or maximum is always attached below the current one.
+	if parent == t.begin && cmp < 0 {
+		t.begin = n
+	}
+
+	if parent == t.end && cmp > 0 {
+		t.end = n
+	}
+
+	t.insertFixup(parent)
+}
+
 // lookup finds the node with the specified key, or nil if not found.
 // Time complexity: O(log n).
 func (t *Tree[K, V]) lookup(key K) *Node[K, V] {
@@ -591,6 +1104,32 @@ func (t *Tree[K, V]) getRightNode(node *Node[K, V]) *Node[K, V] {
 	return node
 }
 
+// next returns the in-order successor of node, or nil if node is the last.
+func (t *Tree[K, V]) next(node *Node[K, V]) *Node[K, V] {
+	if node.right != nil {
+		return t.getLeftNode(node.right)
+	}
+
+	for node.parent != nil && node == node.parent.right {
+		node = node.parent
+	}
+
+	return node.parent
+}
+
+// prev returns the in-order predecessor of node, or nil if node is the first.
+func (t *Tree[K, V]) prev(node *Node[K, V]) *Node[K, V] {
+	if node.left != nil {
+		return t.getRightNode(node.left)
+	}
+
+	for node.parent != nil && node == node.parent.left {
+		node = node.parent
+	}
+
+	return node.parent
+}
+
 // replaceNode replaces the old node with the new node in the tree structure.
 func (t *Tree[K, V]) replaceNode(old, new *Node[K, V]) {
 	if old.parent == nil {
@@ -606,8 +1145,17 @@ func (t *Tree[K, V]) replaceNode(old, new *Node[K, V]) {
 	}
 }
 
+// trace records a rotation around pivot when DeleteTraced is collecting them.
+func (t *Tree[K, V]) trace(rotation string, pivot *Node[K, V]) {
+	if t.rotations != nil {
+		*t.rotations = append(*t.rotations, fmt.Sprintf("%s at %v", rotation, pivot.key))
+	}
+}
+
 // rotateLeft performs a left rotation around the pivot node.
 func (t *Tree[K, V]) rotateLeft(pivot *Node[K, V]) {
+	t.trace("rotateLeft", pivot)
+
 	r := pivot.right
 	t.replaceNode(pivot, r)
 
@@ -625,6 +1173,8 @@ func (t *Tree[K, V]) rotateLeft(pivot *Node[K, V]) {
 
 // rotateRight performs a right rotation around the pivot node.
 func (t *Tree[K, V]) rotateRight(pivot *Node[K, V]) {
+	t.trace("rotateRight", pivot)
+
 	l := pivot.left
 	t.replaceNode(pivot, l)
 
@@ -668,8 +1218,10 @@ func (t *Tree[K, V]) deleteFixup(node *Node[K, V]) {
 		bf := node.b
 		if bf < -1 || bf > 1 {
 			t.rebalance(node)
+			node = node.parent // Continue from the new subtree root.
 		}
 
+		// A non-zero balance means the subtree height is unchanged.
 		if node.b != 0 {
 			break
 		}
@@ -2,11 +2,17 @@ package avltree_test
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand"
 	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/qntx/gods/avltree"
+	"github.com/qntx/gods/container"
 )
 
 func TestAVLTreeGet(t *testing.T) {
@@ -236,6 +242,45 @@ func TestAVLTreeCeilingAndFloor(t *testing.T) {
 	}
 }
 
+func TestAVLTreeNewReverse(t *testing.T) {
+	tree := avltree.NewReverse[int, string]()
+	for _, k := range []int{5, 6, 7, 3, 4, 1, 2} {
+		tree.Put(k, fmt.Sprint(k))
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []int{7, 6, 5, 4, 3, 2, 1}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if key, _, _ := tree.Begin(); key != 7 {
+		t.Errorf("Got %v expected %v", key, 7)
+	}
+
+	if key, _, _ := tree.End(); key != 1 {
+		t.Errorf("Got %v expected %v", key, 1)
+	}
+
+	// Floor and Ceiling follow the tree's order, so they swap roles.
+	tree.Delete(4)
+
+	if node, found := tree.Floor(4); !found || node.Key() != 5 {
+		t.Errorf("Got %v expected %v", node, 5)
+	}
+
+	if node, found := tree.Ceiling(4); !found || node.Key() != 3 {
+		t.Errorf("Got %v expected %v", node, 3)
+	}
+
+	var keys []int
+	for k := range tree.RIter() {
+		keys = append(keys, k)
+	}
+
+	if expectedValue := []int{1, 2, 3, 5, 6, 7}; !slices.Equal(keys, expectedValue) {
+		t.Errorf("Got %v expected %v", keys, expectedValue)
+	}
+}
+
 func TestAVLTreeIterEmpty(t *testing.T) {
 	tree := avltree.New[int, string]()
 	count := 0
@@ -416,3 +461,782 @@ func TestAVLTreeString(t *testing.T) {
 		t.Errorf("String should start with container name")
 	}
 }
+
+func TestAVLTreeGetNodeOrFloor(t *testing.T) {
+	tree := avltree.New[int, string]()
+
+	if node, exact := tree.GetNodeOrFloor(5); node != nil || exact {
+		t.Errorf("Got %v,%v expected %v,%v", node, exact, nil, false)
+	}
+
+	tree.Put(10, "a")
+	tree.Put(20, "b")
+	tree.Put(30, "c")
+
+	tests := []struct {
+		key   int
+		want  int
+		exact bool
+		found bool
+	}{
+		{5, 0, false, false},
+		{10, 10, true, true},
+		{15, 10, false, true},
+		{20, 20, true, true},
+		{29, 20, false, true},
+		{35, 30, false, true},
+	}
+
+	for _, test := range tests {
+		node, exact := tree.GetNodeOrFloor(test.key)
+		if (node != nil) != test.found || exact != test.exact {
+			t.Errorf("key %v: got %v,%v expected found=%v exact=%v", test.key, node, exact, test.found, test.exact)
+
+			continue
+		}
+
+		if node != nil && node.Key() != test.want {
+			t.Errorf("key %v: got %v expected %v", test.key, node.Key(), test.want)
+		}
+	}
+}
+
+func TestAVLTreePutAllAndPutPairs(t *testing.T) {
+	tree := avltree.New[int, string]()
+	tree.Put(2, "old")
+
+	if actualValue := tree.PutAll(map[int]string{1: "a", 2: "b", 3: "c"}); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+
+	if actualValue, expectedValue := tree.Values(), []string{"a", "b", "c"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue := tree.PutPairs([]int{5, 4, 3, 5}, []string{"x", "d", "C", "e"}); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []int{1, 2, 3, 4, 5}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := tree.Values(), []string{"a", "b", "C", "d", "e"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("PutPairs should panic on mismatched lengths")
+		}
+	}()
+
+	tree.PutPairs([]int{1}, nil)
+}
+
+func TestAVLTreeFloorAndCeilingEntry(t *testing.T) {
+	tree := avltree.New[int, string]()
+
+	if _, _, found := tree.FloorEntry(0); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+
+	if _, _, found := tree.CeilingEntry(0); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+
+	tree.Put(10, "a")
+	tree.Put(20, "b")
+	tree.Put(30, "c")
+
+	tests := []struct {
+		key        int
+		floorKey   int
+		floorValue string
+		floorFound bool
+		ceilKey    int
+		ceilValue  string
+		ceilFound  bool
+	}{
+		{5, 0, "", false, 10, "a", true},
+		{10, 10, "a", true, 10, "a", true},
+		{15, 10, "a", true, 20, "b", true},
+		{30, 30, "c", true, 30, "c", true},
+		{35, 30, "c", true, 0, "", false},
+	}
+
+	for _, test := range tests {
+		if k, v, found := tree.FloorEntry(test.key); k != test.floorKey || v != test.floorValue || found != test.floorFound {
+			t.Errorf("FloorEntry(%v): got %v,%v,%v expected %v,%v,%v", test.key, k, v, found, test.floorKey, test.floorValue, test.floorFound)
+		}
+
+		if k, v, found := tree.CeilingEntry(test.key); k != test.ceilKey || v != test.ceilValue || found != test.ceilFound {
+			t.Errorf("CeilingEntry(%v): got %v,%v,%v expected %v,%v,%v", test.key, k, v, found, test.ceilKey, test.ceilValue, test.ceilFound)
+		}
+	}
+}
+
+func TestAVLTreeDeleteReturnsValue(t *testing.T) {
+	tree := avltree.New[int, string]()
+	for i := range 50 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	for i := range 50 {
+		key := i * 13 % 50
+
+		if value, found := tree.Delete(key); value != fmt.Sprint(key) || !found {
+			t.Errorf("Delete(%v): got %v,%v expected %v,%v", key, value, found, fmt.Sprint(key), true)
+		}
+	}
+
+	if value, found := tree.Delete(0); value != "" || found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "", false)
+	}
+}
+
+func TestAVLTreeIterator(t *testing.T) {
+	var it container.ReverseIteratorWithKey[int, string] = avltree.New[int, string]().Iterator()
+	if it.Next() || it.Prev() || it.First() || it.Last() {
+		t.Errorf("Iterator on empty tree should not move")
+	}
+
+	tree := avltree.New[int, string]()
+	for _, k := range []int{5, 3, 8, 1, 4, 7, 9, 2, 6} {
+		tree.Put(k, fmt.Sprint(k))
+	}
+
+	it = tree.Iterator()
+
+	var keys []int
+	for it.Next() {
+		if actualValue, expectedValue := it.Value(), fmt.Sprint(it.Key()); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+
+		keys = append(keys, it.Key())
+	}
+
+	if actualValue, expectedValue := keys, tree.Keys(); !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// Walking back from past the end visits everything in reverse.
+	keys = keys[:0]
+	for it.Prev() {
+		keys = append(keys, it.Key())
+	}
+
+	var reversed []int
+	for k := range tree.RIter() {
+		reversed = append(reversed, k)
+	}
+
+	if !slices.Equal(keys, reversed) {
+		t.Errorf("Got %v expected %v", keys, reversed)
+	}
+
+	rit := tree.ReverseIterator()
+	if !rit.Prev() || rit.Key() != 9 {
+		t.Errorf("Got %v expected %v", rit.Key(), 9)
+	}
+
+	if !rit.Prev() || !rit.Next() || rit.Key() != 9 || rit.Next() {
+		t.Errorf("Iterator should change direction in place")
+	}
+
+	if !rit.First() || rit.Key() != 1 || !rit.Last() || rit.Key() != 9 {
+		t.Errorf("First/Last should reposition the iterator")
+	}
+
+	rit.Begin()
+
+	if rit.Prev() || !rit.Next() || rit.Key() != 1 {
+		t.Errorf("Begin should position the iterator before the first element")
+	}
+}
+
+func TestAVLTreeDeleteAll(t *testing.T) {
+	tree := avltree.New[int, string]()
+	tree.PutPairs([]int{1, 2, 3, 4, 5}, []string{"a", "b", "c", "d", "e"})
+
+	if actualValue := tree.DeleteAll([]int{2, 4, 6, 2}); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []int{1, 3, 5}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue := tree.DeleteAll(nil); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	if actualValue := tree.DeleteAll([]int{1, 3, 5}); actualValue != 3 || !tree.IsEmpty() {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+}
+
+func TestAVLSyncTree(t *testing.T) {
+	tree := avltree.NewSync[int, int]()
+
+	var wg sync.WaitGroup
+
+	for i := range 100 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			tree.Put(i, i*10)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			tree.Get(i)
+			tree.FloorEntry(i)
+		}()
+	}
+
+	wg.Wait()
+
+	if actualValue := tree.Len(); actualValue != 100 {
+		t.Errorf("Got %v expected %v", actualValue, 100)
+	}
+
+	// Concurrent increments through WithWrite must not lose updates.
+	for range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			tree.WithWrite(func(t *avltree.Tree[int, int]) {
+				v, _ := t.Get(0)
+				t.Put(0, v+1)
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	if actualValue, _ := tree.Get(0); actualValue != 50 {
+		t.Errorf("Got %v expected %v", actualValue, 50)
+	}
+
+	if k, v, ok := tree.CeilingEntry(55); k != 55 || v != 550 || !ok {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", k, v, ok, 55, 550, true)
+	}
+
+	sum := 0
+
+	tree.WithRead(func(t *avltree.Tree[int, int]) {
+		for k := range t.Iter() {
+			sum += k
+		}
+	})
+
+	if sum != 4950 {
+		t.Errorf("Got %v expected %v", sum, 4950)
+	}
+
+	if v, found := tree.Delete(1); v != 10 || !found || tree.Has(1) {
+		t.Errorf("Got %v,%v expected %v,%v", v, found, 10, true)
+	}
+
+	tree.Clear()
+
+	if !tree.IsEmpty() || len(tree.Keys()) != 0 || len(tree.Values()) != 0 {
+		t.Errorf("Tree should be empty after Clear")
+	}
+}
+
+func TestAVLTreeNearest(t *testing.T) {
+	dist := func(a, b int) int {
+		if a > b {
+			return a - b
+		}
+
+		return b - a
+	}
+
+	tree := avltree.New[int, string]()
+
+	if _, _, found := tree.Nearest(5, dist); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+
+	tree.Put(10, "a")
+	tree.Put(20, "b")
+	tree.Put(30, "c")
+
+	tests := []struct {
+		key      int
+		expected int
+	}{
+		{-5, 10},
+		{10, 10},
+		{14, 10},
+		{15, 10}, // Tie goes to the floor.
+		{16, 20},
+		{29, 30},
+		{100, 30},
+	}
+
+	for _, test := range tests {
+		k, v, found := tree.Nearest(test.key, dist)
+		if expectedValue, _ := tree.Get(test.expected); k != test.expected || v != expectedValue || !found {
+			t.Errorf("Nearest(%v): got %v,%v,%v expected %v,%v,%v", test.key, k, v, found, test.expected, expectedValue, true)
+		}
+	}
+}
+
+func TestAVLTreeRange(t *testing.T) {
+	tree := avltree.New[int, string]()
+
+	for range tree.Range(0, 10) {
+		t.Errorf("Shouldn't iterate on empty tree")
+	}
+
+	for i := 0; i < 20; i += 2 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	collect := func(seq iter.Seq2[int, string]) []int {
+		var keys []int
+
+		for k, v := range seq {
+			if v != fmt.Sprint(k) {
+				t.Errorf("Got %v expected %v", v, fmt.Sprint(k))
+			}
+
+			keys = append(keys, k)
+		}
+
+		return keys
+	}
+
+	tests := []struct {
+		low, high int
+		expected  []int
+	}{
+		{4, 10, []int{4, 6, 8, 10}},
+		{3, 11, []int{4, 6, 8, 10}},
+		{-5, 3, []int{0, 2}},
+		{15, 100, []int{16, 18}},
+		{5, 5, nil},
+		{6, 6, []int{6}},
+		{10, 4, nil},
+		{100, 200, nil},
+	}
+
+	for _, test := range tests {
+		if actualValue := collect(tree.Range(test.low, test.high)); !slices.Equal(actualValue, test.expected) {
+			t.Errorf("Range(%v, %v): got %v expected %v", test.low, test.high, actualValue, test.expected)
+		}
+
+		reversed := slices.Clone(test.expected)
+		slices.Reverse(reversed)
+
+		if actualValue := collect(tree.RangeReverse(test.low, test.high)); !slices.Equal(actualValue, reversed) {
+			t.Errorf("RangeReverse(%v, %v): got %v expected %v", test.low, test.high, actualValue, reversed)
+		}
+	}
+
+	for k := range tree.Range(0, 18) {
+		if k > 4 {
+			break
+		}
+	}
+}
+
+func TestAVLTreePopMinMax(t *testing.T) {
+	tree := avltree.New[int, string]()
+	tree.PutPairs([]int{3, 1, 2}, []string{"c", "a", "b"})
+
+	if k, v, found := tree.PopMin(); k != 1 || v != "a" || !found {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", k, v, found, 1, "a", true)
+	}
+
+	if k, v, found := tree.PopMax(); k != 3 || v != "c" || !found {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", k, v, found, 3, "c", true)
+	}
+
+	tree.PopMin()
+
+	if _, _, found := tree.PopMax(); found || !tree.IsEmpty() {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+}
+
+func TestAVLTreeDeleteTraced(t *testing.T) {
+	// Left-Left: deleting 9 leaves 5 left-heavy with a left-heavy left child.
+	//
+	//        5                3
+	//      3   8    ==>     2   5
+	//     2 4    9         1   4 8
+	//    1
+	tree := avltree.New[int, struct{}]()
+	for _, k := range []int{5, 3, 8, 2, 4, 9, 1} {
+		tree.Put(k, struct{}{})
+	}
+
+	removed, rotations := tree.DeleteTraced(9)
+	if expected := []string{"rotateRight at 5"}; !removed || !slices.Equal(rotations, expected) {
+		t.Errorf("Got %v,%v expected %v,%v", removed, rotations, true, expected)
+	}
+
+	if actualValue := tree.GetNode(3).Parent(); actualValue != nil {
+		t.Errorf("Got parent %v expected %v to be the root", actualValue, 3)
+	}
+
+	// Left-Right: deleting 9 leaves 5 left-heavy with a right-heavy left child.
+	//
+	//        5                4
+	//      2   8    ==>     2   5
+	//     1 4    9         1 3   8
+	//      3
+	tree.Clear()
+
+	for _, k := range []int{5, 2, 8, 1, 4, 9, 3} {
+		tree.Put(k, struct{}{})
+	}
+
+	removed, rotations = tree.DeleteTraced(9)
+	if expected := []string{"rotateLeft at 2", "rotateRight at 5"}; !removed || !slices.Equal(rotations, expected) {
+		t.Errorf("Got %v,%v expected %v,%v", removed, rotations, true, expected)
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []int{1, 2, 3, 4, 5, 8}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// No rotation needed.
+	removed, rotations = tree.DeleteTraced(1)
+	if !removed || len(rotations) != 0 {
+		t.Errorf("Got %v,%v expected %v,%v", removed, rotations, true, []string{})
+	}
+
+	// Missing key.
+	removed, rotations = tree.DeleteTraced(42)
+	if removed || rotations != nil {
+		t.Errorf("Got %v,%v expected %v,%v", removed, rotations, false, nil)
+	}
+}
+
+func TestAVLTreeDeleteKeepsBalance(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for range 50 {
+		tree := avltree.New[int, int]()
+		for range 100 {
+			tree.Put(rng.Intn(150), 0)
+		}
+
+		for range 120 {
+			key := rng.Intn(150)
+			tree.Delete(key)
+
+			root := tree.GetBeginNode()
+			for root != nil && root.Parent() != nil {
+				root = root.Parent()
+			}
+
+			if _, ok := assertAVLHeight(root); !ok {
+				t.Fatalf("tree unbalanced after deleting %v", key)
+			}
+		}
+	}
+}
+
+// assertAVLHeight returns the height of the subtree rooted at n and whether
+// every node in it satisfies the AVL balance property.
+func assertAVLHeight[K comparable, V any](n *avltree.Node[K, V]) (int, bool) {
+	if n == nil {
+		return -1, true
+	}
+
+	l, okL := assertAVLHeight(n.Left())
+	r, okR := assertAVLHeight(n.Right())
+
+	return 1 + max(l, r), okL && okR && r-l >= -1 && r-l <= 1
+}
+
+func TestAVLTreeBeginEndCache(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	tree := avltree.New[int, int]()
+
+	assertBeginEnd := func(step int) {
+		t.Helper()
+
+		keys := tree.Keys()
+		begin, end := tree.GetBeginNode(), tree.GetEndNode()
+
+		if len(keys) == 0 {
+			if begin != nil || end != nil {
+				t.Fatalf("step %v: got %v,%v expected nil,nil", step, begin, end)
+			}
+
+			return
+		}
+
+		if begin == nil || begin.Key() != keys[0] || begin.Left() != nil {
+			t.Fatalf("step %v: got begin %v expected %v", step, begin, keys[0])
+		}
+
+		if end == nil || end.Key() != keys[len(keys)-1] || end.Right() != nil {
+			t.Fatalf("step %v: got end %v expected %v", step, end, keys[len(keys)-1])
+		}
+	}
+
+	for step := range 5000 {
+		key := rng.Intn(200)
+
+		switch op := rng.Intn(10); {
+		case op < 5:
+			tree.Put(key, step)
+		case op < 8:
+			tree.Delete(key)
+		case op < 9:
+			tree.DeleteBegin()
+		default:
+			tree.DeleteEnd()
+		}
+
+		assertBeginEnd(step)
+	}
+
+	clone := tree.Clone().(*avltree.Tree[int, int])
+	tree.Clear()
+	assertBeginEnd(-1)
+
+	if k, _, ok := clone.Begin(); !ok || k != clone.Keys()[0] {
+		t.Errorf("Got %v,%v expected %v,%v", k, ok, clone.Keys()[0], true)
+	}
+}
+
+func TestAVLTreeShapeSerialization(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	tree := avltree.New[int, string]()
+
+	for range 300 {
+		k := rng.Intn(1000)
+		tree.Put(k, fmt.Sprint(k))
+	}
+
+	for range 100 {
+		tree.Delete(rng.Intn(1000))
+	}
+
+	data, err := tree.MarshalShape()
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	loaded := avltree.New[int, string]()
+	loaded.Put(-1, "stale")
+
+	if err := loaded.UnmarshalShape(data); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if actualValue, expectedValue := loaded.Len(), tree.Len(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := loaded.String(), tree.String(); actualValue != expectedValue {
+		t.Errorf("Reloaded tree has a different shape:\n%v\nexpected\n%v", actualValue, expectedValue)
+	}
+
+	if actualKeys, actualValues := loaded.Entries(); !slices.Equal(actualKeys, tree.Keys()) || !slices.Equal(actualValues, tree.Values()) {
+		t.Errorf("Got %v expected %v", actualKeys, tree.Keys())
+	}
+
+	if k, _, _ := loaded.Begin(); k != tree.Keys()[0] {
+		t.Errorf("Got %v expected %v", k, tree.Keys()[0])
+	}
+
+	// The reloaded tree must keep working.
+	for k := range 1000 {
+		loaded.Put(k, "")
+	}
+
+	for k := range 900 {
+		loaded.Delete(k)
+	}
+
+	if actualValue := loaded.Keys(); len(actualValue) != 100 || actualValue[0] != 900 {
+		t.Errorf("Got %v keys starting at %v expected %v starting at %v", len(actualValue), actualValue[0], 100, 900)
+	}
+
+	empty := avltree.New[int, string]()
+
+	data, err = empty.MarshalShape()
+	if err != nil || string(data) != "null" {
+		t.Errorf("Got %s,%v expected %v,%v", data, err, "null", nil)
+	}
+
+	if err := loaded.UnmarshalShape(data); err != nil || !loaded.IsEmpty() || loaded.GetBeginNode() != nil {
+		t.Errorf("Got %v,%v expected an empty tree", err, loaded.Len())
+	}
+
+	invalid := []string{
+		`{"k":2,"v":"","b":0,"l":{"k":3,"v":"","b":0},"r":{"k":4,"v":"","b":0}}`, // out of order
+		`{"k":2,"v":"","b":0,"l":{"k":1,"v":"","b":0}}`,                          // wrong balance
+		`{"k":1,"v":"","b":2,"r":{"k":2,"v":"","b":1,"r":{"k":3,"v":"","b":0}}}`, // unbalanced
+	}
+
+	for _, input := range invalid {
+		tree := avltree.New[int, string]()
+		tree.Put(7, "kept")
+
+		if err := tree.UnmarshalShape([]byte(input)); !errors.Is(err, avltree.ErrInvalidShape) {
+			t.Errorf("Got error %v expected %v for %s", err, avltree.ErrInvalidShape, input)
+		}
+
+		if actualValue := tree.Keys(); !slices.Equal(actualValue, []int{7}) {
+			t.Errorf("Tree should be unchanged after a failed load, got %v", actualValue)
+		}
+	}
+
+	if err := tree.UnmarshalShape([]byte(`{"k":`)); err == nil {
+		t.Errorf("Expected an error for malformed JSON")
+	}
+}
+
+func TestAVLTreeTrim(t *testing.T) {
+	tree := avltree.New[int, int]()
+
+	if actualValue := tree.Trim(0, 10); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	for i := range 100 {
+		tree.Put(i, i)
+	}
+
+	if actualValue := tree.Trim(20, 79); actualValue != 40 {
+		t.Errorf("Got %v expected %v", actualValue, 40)
+	}
+
+	keys := tree.Keys()
+	if len(keys) != 60 || keys[0] != 20 || keys[len(keys)-1] != 79 {
+		t.Errorf("Got %v expected keys 20..79", keys)
+	}
+
+	root := tree.GetBeginNode()
+	for root != nil && root.Parent() != nil {
+		root = root.Parent()
+	}
+
+	if _, ok := assertAVLHeight(root); !ok {
+		t.Errorf("tree unbalanced after Trim")
+	}
+
+	// Bounds need not be present, and the begin/end cache follows the trim.
+	if actualValue := tree.Trim(-5, 49); actualValue != 30 {
+		t.Errorf("Got %v expected %v", actualValue, 30)
+	}
+
+	if k, _, _ := tree.Begin(); k != 20 {
+		t.Errorf("Got %v expected %v", k, 20)
+	}
+
+	if k, _, _ := tree.End(); k != 49 {
+		t.Errorf("Got %v expected %v", k, 49)
+	}
+
+	if actualValue := tree.Trim(0, 100); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	if actualValue := tree.Trim(40, 30); actualValue != 30 || !tree.IsEmpty() {
+		t.Errorf("Got %v expected %v and an empty tree", actualValue, 30)
+	}
+
+	if node := tree.GetBeginNode(); node != nil {
+		t.Errorf("Got %v expected %v", node, nil)
+	}
+}
+
+func TestAVLTreeGetOrCompute(t *testing.T) {
+	tree := avltree.New[int, string]()
+	calls := 0
+
+	compute := func(v string) func() string {
+		return func() string {
+			calls++
+
+			return v
+		}
+	}
+
+	for _, k := range []int{5, 3, 8, 1, 4, 7, 9, 2, 6} {
+		if value, loaded := tree.GetOrCompute(k, compute(fmt.Sprint(k))); value != fmt.Sprint(k) || loaded {
+			t.Errorf("Got %v,%v expected %v,%v", value, loaded, fmt.Sprint(k), false)
+		}
+	}
+
+	if calls != 9 {
+		t.Errorf("Got %v calls expected %v", calls, 9)
+	}
+
+	// A hit returns the stored value without calling compute.
+	if value, loaded := tree.GetOrCompute(4, compute("x")); value != "4" || !loaded || calls != 9 {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", value, loaded, calls, "4", true, 9)
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []int{1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	root := tree.GetBeginNode()
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+
+	if _, ok := assertAVLHeight(root); !ok {
+		t.Errorf("tree unbalanced after GetOrCompute")
+	}
+
+	// Inserting new extremes keeps the begin/end cache current.
+	tree.GetOrCompute(0, compute("0"))
+	tree.GetOrCompute(10, compute("10"))
+
+	if k, _, _ := tree.Begin(); k != 0 {
+		t.Errorf("Got %v expected %v", k, 0)
+	}
+
+	if k, _, _ := tree.End(); k != 10 {
+		t.Errorf("Got %v expected %v", k, 10)
+	}
+
+	syncTree := avltree.NewSync[int, int]()
+
+	var wg sync.WaitGroup
+
+	var computed sync.Map
+
+	for i := range 100 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			syncTree.GetOrCompute(i%10, func() int {
+				if _, dup := computed.LoadOrStore(i%10, true); dup {
+					t.Errorf("compute called twice for %v", i%10)
+				}
+
+				return i % 10
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	if actualValue := syncTree.Len(); actualValue != 10 {
+		t.Errorf("Got %v expected %v", actualValue, 10)
+	}
+}
@@ -0,0 +1,180 @@
+package avltree
+
+import (
+	"sync"
+
+	"github.com/qntx/gods/cmp"
+)
+
+// SyncTree is a concurrency-safe wrapper around Tree.
+// Lookups take a shared read lock and modifications an exclusive write lock,
+// so concurrent readers do not block each other. WithRead and WithWrite run
+// compound operations atomically under a single lock.
+//
+// Nodes, iterators and iter.Seq2 sequences obtained from the underlying Tree
+// inside WithRead or WithWrite must not be used after the callback returns,
+// as they are not protected once the lock is released.
+type SyncTree[K comparable, V any] struct {
+	mu   sync.RWMutex
+	tree *Tree[K, V]
+}
+
+// NewSync creates a new concurrency-safe AVL tree with the built-in comparator
+// for ordered types.
+func NewSync[K cmp.Ordered, V any]() *SyncTree[K, V] {
+	return &SyncTree[K, V]{tree: New[K, V]()}
+}
+
+// NewSyncWith creates a new concurrency-safe AVL tree with a custom comparator.
+func NewSyncWith[K comparable, V any](cmp cmp.Comparator[K]) *SyncTree[K, V] {
+	return &SyncTree[K, V]{tree: NewWith[K, V](cmp)}
+}
+
+// Put inserts or updates a key-value pair.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) Put(key K, val V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tree.Put(key, val)
+}
+
+// GetOrCompute returns the value stored for key, or inserts and returns the
+// result of compute if the key is absent, all under the write lock. compute
+// runs at most once, while the lock is held, and must not use s.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) GetOrCompute(key K, compute func() V) (value V, loaded bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tree.GetOrCompute(key, compute)
+}
+
+// Delete removes the node with the specified key.
+// Returns the removed value and true if the key was found.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) Delete(key K) (value V, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tree.Delete(key)
+}
+
+// Clear removes all nodes from the tree.
+// Time complexity: O(1).
+func (s *SyncTree[K, V]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tree.Clear()
+}
+
+// Get retrieves the value associated with the specified key.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) Get(key K) (val V, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.Get(key)
+}
+
+// Has checks if the specified key exists in the tree.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) Has(key K) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.Has(key)
+}
+
+// FloorEntry returns the largest key less than or equal to the given key,
+// along with its value. Unlike Tree.Floor it returns copies rather than a
+// node, so nothing escapes the lock.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) FloorEntry(key K) (floorKey K, value V, found bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.FloorEntry(key)
+}
+
+// CeilingEntry returns the smallest key greater than or equal to the given
+// key, along with its value. Unlike Tree.Ceiling it returns copies rather
+// than a node, so nothing escapes the lock.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) CeilingEntry(key K) (ceilingKey K, value V, found bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.CeilingEntry(key)
+}
+
+// Keys returns all keys in in-order sequence.
+// Time complexity: O(n).
+func (s *SyncTree[K, V]) Keys() []K {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.Keys()
+}
+
+// Values returns all values in in-order sequence of their keys.
+// Time complexity: O(n).
+func (s *SyncTree[K, V]) Values() []V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.Values()
+}
+
+// Len returns the number of nodes in the tree.
+// Time complexity: O(1).
+func (s *SyncTree[K, V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.Len()
+}
+
+// IsEmpty checks if the tree contains no nodes.
+// Time complexity: O(1).
+func (s *SyncTree[K, V]) IsEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.IsEmpty()
+}
+
+// WithRead calls f with the underlying tree while holding the read lock.
+// f must not modify the tree.
+//
+// Example:
+//
+//	st.WithRead(func(t *Tree[string, int]) {
+//		for k, v := range t.Iter() {
+//			fmt.Println(k, v)
+//		}
+//	})
+func (s *SyncTree[K, V]) WithRead(f func(t *Tree[K, V])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f(s.tree)
+}
+
+// WithWrite calls f with the underlying tree while holding the write lock,
+// making compound operations atomic.
+//
+// Example:
+//
+//	st.WithWrite(func(t *Tree[string, int]) {
+//		if !t.Has("k") {
+//			t.Put("k", 1)
+//		}
+//	})
+func (s *SyncTree[K, V]) WithWrite(f func(t *Tree[K, V])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f(s.tree)
+}
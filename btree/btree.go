@@ -17,7 +17,9 @@
 package btree
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"iter"
 	"maps"
@@ -31,6 +33,10 @@ import (
 // notFound is a sentinel value indicating a key was not found.
 const notFound = -1
 
+// bulkDeleteRatio is the tree size, relative to the number of keys, below
+// which DeleteMany rebuilds the tree instead of deleting keys one by one.
+const bulkDeleteRatio = 4
+
 // entry represents an internal key-value pair within a B-tree node.
 type entry[K comparable, V any] struct {
 	key   K
@@ -57,6 +63,7 @@ type Node[K comparable, V any] struct {
 	parent   *Node[K, V]
 	children []*Node[K, V]  // Children nodes
 	entries  []*entry[K, V] // Contained entries in node
+	size     int            // Number of entries in the subtree rooted at this node
 }
 
 // Parent returns the parent of the node.
@@ -65,10 +72,20 @@ func (n *Node[K, V]) Parent() *Node[K, V] {
 }
 
 // Entries returns the key-value pairs of the node.
+// The entry type is unexported; read its fields with Key and Value, or use
+// EntryAt.
 func (n *Node[K, V]) Entries() []*entry[K, V] {
 	return n.entries
 }
 
+// EntryAt returns the key and value of the i-th entry of the node, in sorted
+// order. Panics if i is not in [0, len(n.Entries())).
+func (n *Node[K, V]) EntryAt(i int) (K, V) {
+	e := n.entries[i]
+
+	return e.key, e.value
+}
+
 // Children returns the children nodes of the node.
 func (n *Node[K, V]) Children() []*Node[K, V] {
 	return n.children
@@ -120,16 +137,63 @@ func (n *Node[K, V]) String() string {
 	return sb.String()
 }
 
+// ErrUninitialized is returned by UnmarshalJSON when the receiver was not
+// created with New, NewWith or NewWithBias, so it has no order or comparator.
+var ErrUninitialized = errors.New("btree: tree must be created with New or NewWith before unmarshaling")
+
 var _ container.OrderedMap[int, int] = (*Tree[int, int])(nil)
 var _ json.Marshaler = (*Tree[string, int])(nil)
 var _ json.Unmarshaler = (*Tree[string, int])(nil)
 
+// SplitBias selects which median entry is promoted when a full node splits.
+//
+// A node overflows with m entries. For odd orders m the median is unique and
+// both biases behave identically. For even orders there are two medians:
+// LeftBias promotes the lower one, leaving the right node one entry fuller,
+// and RightBias promotes the upper one, leaving the left node one entry
+// fuller. With monotonically increasing keys, every insert lands in the
+// rightmost leaf and the left nodes are never touched again, so RightBias
+// yields fuller nodes and fewer of them. Both keep all B-tree invariants.
+type SplitBias int
+
+const (
+	// LeftBias promotes the lower median, (m-1)/2. This is the default.
+	LeftBias SplitBias = iota
+	// RightBias promotes the upper median, m/2, favoring append-heavy workloads.
+	RightBias
+)
+
+// JSONFormat selects the encoding produced by MarshalJSON.
+//
+// JSONObject, the default, encodes the tree as a JSON object, which requires
+// keys that encoding/json can use as object keys (strings, integers or
+// encoding.TextMarshalers) and loses the key order. JSONArray encodes it as an
+// array of {"key": ..., "value": ...} objects in sorted key order, which
+// supports any key type. UnmarshalJSON accepts both forms regardless.
+type JSONFormat int
+
+const (
+	// JSONObject encodes the tree as {"key": value, ...}. This is the default.
+	JSONObject JSONFormat = iota
+	// JSONArray encodes the tree as [{"key": key, "value": value}, ...] in
+	// sorted key order.
+	JSONArray
+)
+
+// jsonEntry is the encoding of an entry in the JSONArray format.
+type jsonEntry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
 // Tree holds the elements and configuration of the B-tree.
 type Tree[K comparable, V any] struct {
-	root *Node[K, V]       // Root node of the tree.
-	cmp  cmp.Comparator[K] // Key comparator.
-	len  int               // Total number of key-value pairs in the tree.
-	m    int               // Order (maximum number of children).
+	root   *Node[K, V]       // Root node of the tree.
+	cmp    cmp.Comparator[K] // Key comparator.
+	len    int               // Total number of key-value pairs in the tree.
+	m      int               // Order (maximum number of children).
+	bias   SplitBias         // Median selection when splitting.
+	format JSONFormat        // Encoding produced by MarshalJSON.
 }
 
 // Root returns the root node of the tree.
@@ -165,13 +229,27 @@ func NewWith[K comparable, V any](order int, cmp cmp.Comparator[K]) *Tree[K, V]
 	return &Tree[K, V]{m: order, cmp: cmp}
 }
 
+// NewWithBias creates a new B-tree with a custom comparator and split bias.
+// The order `m` must be 3 or greater. Panics if order is invalid.
+// Time complexity: O(1).
+//
+// Example:
+//
+//	tree := NewWithBias[int, string](4, cmp.Compare[int], RightBias)
+func NewWithBias[K comparable, V any](order int, cmp cmp.Comparator[K], bias SplitBias) *Tree[K, V] {
+	t := NewWith[K, V](order, cmp)
+	t.bias = bias
+
+	return t
+}
+
 // Put inserts a key-value pair into the tree, updating the value if the key already exists.
 // Time complexity: O(log n).
 func (t *Tree[K, V]) Put(key K, value V) {
 	e := &entry[K, V]{key: key, value: value}
 
 	if t.root == nil {
-		t.root = &Node[K, V]{entries: []*entry[K, V]{e}}
+		t.root = &Node[K, V]{entries: []*entry[K, V]{e}, size: 1}
 		t.len++
 
 		return
@@ -182,6 +260,49 @@ func (t *Tree[K, V]) Put(key K, value V) {
 	}
 }
 
+// Upsert inserts a key-value pair, or, if the key already exists, replaces its
+// value with merge(old, value). merge is not called for fresh inserts.
+// The tree is descended only once.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) Upsert(key K, value V, merge func(old, new V) V) {
+	if t.root == nil {
+		t.Put(key, value)
+
+		return
+	}
+
+	node := t.root
+
+	for {
+		index, found := t.search(node, key)
+		if found {
+			e := node.entries[index]
+			e.value = merge(e.value, value)
+
+			return
+		}
+
+		if node.isLeaf() {
+			t.insertAt(node, index, &entry[K, V]{key: key, value: value})
+			t.len++
+
+			return
+		}
+
+		node = node.children[index]
+	}
+}
+
+// MapValues replaces every value with f(key, value) in place, visiting each
+// entry exactly once in sorted key order. Keys and tree shape are unchanged.
+// Time complexity: O(n).
+func (t *Tree[K, V]) MapValues(f func(K, V) V) {
+	for node, i := t.GetBeginNode(), 0; node != nil; node, i = successor(node, i) {
+		e := node.entries[i]
+		e.value = f(e.key, e.value)
+	}
+}
+
 // Get retrieves the value for a given key.
 // Returns the value and true if found, or the zero value and false otherwise.
 // Time complexity: O(log n).
@@ -196,6 +317,22 @@ func (t *Tree[K, V]) Get(key K) (V, bool) {
 	return zeroV, false
 }
 
+// GetRef returns a pointer to the value stored for the given key, so that
+// large values can be read or mutated in place without copying.
+// Returns nil and false if the key is not found.
+//
+// The pointer stays valid until the key is overwritten by Put or removed;
+// after such mutations it no longer refers to the value held by the tree.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) GetRef(key K) (*V, bool) {
+	node, index := t.lookup(key)
+	if index == notFound {
+		return nil, false
+	}
+
+	return &node.entries[index].value, true
+}
+
 // GetNode retrieves the node containing the given key.
 // Returns the node if found, nil otherwise.
 // Time complexity: O(log n).
@@ -236,6 +373,57 @@ func (t *Tree[K, V]) Delete(key K) (v V, found bool) {
 	return v, true
 }
 
+// DeleteMany removes every key in keys that is present in the tree and
+// returns the number of keys removed. Absent and repeated keys are skipped.
+//
+// The keys are sorted first. Small batches are then deleted one by one in
+// order. Large batches, such as a clustered range, are removed in a single
+// pass that keeps the surviving entries and rebuilds the tree from them,
+// which avoids the borrow and merge cascades of repeated Delete calls. In
+// that case the resulting nodes are packed as full as the order allows.
+//
+// Time complexity: O(k log k + k log n) for small batches and
+// O(k log k + n) for large ones, for k keys.
+func (t *Tree[K, V]) DeleteMany(keys []K) int {
+	if t.len == 0 || len(keys) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(keys)
+	slices.SortFunc(sorted, t.cmp)
+	sorted = slices.CompactFunc(sorted, func(a, b K) bool { return t.cmp(a, b) == 0 })
+
+	if len(sorted)*bulkDeleteRatio < t.len {
+		prev := t.len
+		for _, k := range sorted {
+			t.Delete(k)
+		}
+
+		return prev - t.len
+	}
+
+	entries := appendEntries(make([]*entry[K, V], 0, t.len), t.root)
+	kept := entries[:0]
+	i := 0
+
+	for _, e := range entries {
+		for i < len(sorted) && t.cmp(sorted[i], e.key) < 0 {
+			i++
+		}
+
+		if i < len(sorted) && t.cmp(sorted[i], e.key) == 0 {
+			continue
+		}
+
+		kept = append(kept, e)
+	}
+
+	removed := t.len - len(kept)
+	t.build(kept)
+
+	return removed
+}
+
 // Begin returns the minimum key-value pair.
 // Time complexity: O(log n).
 func (t *Tree[K, V]) Begin() (k K, v V, ok bool) {
@@ -290,10 +478,30 @@ func (t *Tree[K, V]) DeleteEnd() (k K, v V, ok bool) {
 	return k, v, false
 }
 
+// PopMin removes and returns the minimum key-value pair.
+// Returns the removed key, value, and true, or zero values and false if the
+// tree is empty. Alias of DeleteBegin, named consistently across the tree packages.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) PopMin() (key K, value V, found bool) {
+	return t.DeleteBegin()
+}
+
+// PopMax removes and returns the maximum key-value pair.
+// Returns the removed key, value, and true, or zero values and false if the
+// tree is empty. Alias of DeleteEnd, named consistently across the tree packages.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) PopMax() (key K, value V, found bool) {
+	return t.DeleteEnd()
+}
+
 // GetBeginNode returns the node with the minimum key.
 // Returns nil if the tree is empty.
 // Time complexity: O(log n).
 func (tree *Tree[K, V]) GetBeginNode() *Node[K, V] {
+	if tree.IsEmpty() {
+		return nil
+	}
+
 	return getMinNode(tree.root)
 }
 
@@ -301,6 +509,10 @@ func (tree *Tree[K, V]) GetBeginNode() *Node[K, V] {
 // Returns nil if the tree is empty.
 // Time complexity: O(log n).
 func (tree *Tree[K, V]) GetEndNode() *Node[K, V] {
+	if tree.IsEmpty() {
+		return nil
+	}
+
 	return getMaxNode(tree.root)
 }
 
@@ -308,13 +520,58 @@ func (tree *Tree[K, V]) GetEndNode() *Node[K, V] {
 // Returns 0 if the tree is empty.
 // Time complexity: O(log n).
 func (t *Tree[K, V]) Height() int {
-	if t.root == nil {
+	if t.IsEmpty() {
 		return 0
 	}
 
 	return t.root.height()
 }
 
+// WalkBFS visits every node in level order (breadth-first), calling f with the
+// node's depth, where the root is at depth 0. Nodes at the same depth are
+// visited left to right. Does nothing if the tree is empty.
+// Time complexity: O(number of nodes).
+func (t *Tree[K, V]) WalkBFS(f func(depth int, n *Node[K, V])) {
+	if t.IsEmpty() {
+		return
+	}
+
+	type item struct {
+		node  *Node[K, V]
+		depth int
+	}
+
+	queue := []item{{t.root, 0}}
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+
+		f(it.depth, it.node)
+
+		for _, c := range it.node.children {
+			queue = append(queue, item{c, it.depth + 1})
+		}
+	}
+}
+
+// Levels returns the tree's nodes grouped by depth, with the root alone at
+// level 0 and each level ordered left to right. Useful for inspecting shape,
+// fan-out and fill factor. Returns nil if the tree is empty.
+// Time complexity: O(number of nodes).
+func (t *Tree[K, V]) Levels() [][]*Node[K, V] {
+	var levels [][]*Node[K, V]
+
+	t.WalkBFS(func(depth int, n *Node[K, V]) {
+		if depth == len(levels) {
+			levels = append(levels, nil)
+		}
+
+		levels[depth] = append(levels[depth], n)
+	})
+
+	return levels
+}
+
 // Len returns the number of items in the tree. Time complexity: O(1).
 func (t *Tree[K, V]) Len() int { return t.len }
 
@@ -327,6 +584,28 @@ func (t *Tree[K, V]) Clear() {
 	t.len = 0
 }
 
+// Reset removes all items from the tree while keeping a single empty root node,
+// so that the next Put does not need to allocate a fresh root.
+//
+// The order and comparator are preserved. All entry and child references held by
+// the root are released so the removed keys and values can be garbage collected.
+// Time complexity: O(m).
+func (t *Tree[K, V]) Reset() {
+	t.len = 0
+
+	if t.root == nil {
+		t.root = &Node[K, V]{entries: make([]*entry[K, V], 0, t.m)}
+
+		return
+	}
+
+	clear(t.root.entries)
+	t.root.entries = t.root.entries[:0]
+	t.root.children = nil
+	t.root.parent = nil
+	t.root.size = 0
+}
+
 // Keys returns a slice of all keys in sorted order. Time complexity: O(n).
 func (t *Tree[K, V]) Keys() []K {
 	keys := make([]K, 0, t.len)
@@ -367,7 +646,7 @@ func (t *Tree[K, V]) Entries() ([]K, []V) {
 
 // Clone creates a deep copy of the tree. Time complexity: O(n).
 func (t *Tree[K, V]) Clone() container.Map[K, V] {
-	newTree := &Tree[K, V]{m: t.m, cmp: t.cmp, len: t.len}
+	newTree := &Tree[K, V]{m: t.m, cmp: t.cmp, len: t.len, bias: t.bias, format: t.format}
 	if t.root != nil {
 		newTree.root = cloneNode(t.root, nil)
 	}
@@ -375,6 +654,27 @@ func (t *Tree[K, V]) Clone() container.Map[K, V] {
 	return newTree
 }
 
+// Rebuild returns a new tree of order newOrder holding copies of t's entries,
+// with the same comparator, split bias and JSON format. t is left unchanged.
+//
+// The entries are already sorted, so the new tree is bulk-built bottom-up with
+// fully packed nodes instead of being filled by repeated Put calls.
+// Panics if newOrder is less than 3.
+// Time complexity: O(n).
+func (t *Tree[K, V]) Rebuild(newOrder int) *Tree[K, V] {
+	newTree := NewWithBias[K, V](newOrder, t.cmp, t.bias)
+	newTree.format = t.format
+
+	entries := appendEntries(make([]*entry[K, V], 0, t.len), t.root)
+	for i, e := range entries {
+		entries[i] = &entry[K, V]{key: e.key, value: e.value}
+	}
+
+	newTree.build(entries)
+
+	return newTree
+}
+
 // Iter returns an iterator for in-order traversal.
 func (t *Tree[K, V]) Iter() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
@@ -389,6 +689,307 @@ func (t *Tree[K, V]) RIter() iter.Seq2[K, V] {
 	}
 }
 
+// SubtreeIter returns an iterator for in-order traversal of the subtree
+// rooted at n, e.g. a node found with GetNode or Levels. The entries yielded
+// form a contiguous key range of the tree. A nil node yields nothing.
+//
+// n must belong to t, and t must not be modified during iteration.
+// Time complexity: O(k) for the k entries in the subtree.
+func (t *Tree[K, V]) SubtreeIter(n *Node[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		inorder(n, yield)
+	}
+}
+
+// Ascend calls f for every entry in ascending key order, stopping early if f
+// returns false.
+// Time complexity: O(n).
+func (t *Tree[K, V]) Ascend(f func(K, V) bool) {
+	node, i := t.first()
+	t.ascend(node, i, nil, f)
+}
+
+// AscendRange calls f for every entry with low <= key < high in ascending key
+// order, stopping early if f returns false.
+// Time complexity: O(log n + k) for k visited entries.
+func (t *Tree[K, V]) AscendRange(low, high K, f func(K, V) bool) {
+	node, i := t.ceiling(low)
+	t.ascend(node, i, &high, f)
+}
+
+// AscendGreaterOrEqual calls f for every entry with key >= pivot in ascending
+// key order, stopping early if f returns false.
+// Time complexity: O(log n + k) for k visited entries.
+func (t *Tree[K, V]) AscendGreaterOrEqual(pivot K, f func(K, V) bool) {
+	node, i := t.ceiling(pivot)
+	t.ascend(node, i, nil, f)
+}
+
+// AscendLessThan calls f for every entry with key < pivot in ascending key
+// order, stopping early if f returns false.
+// Time complexity: O(log n + k) for k visited entries.
+func (t *Tree[K, V]) AscendLessThan(pivot K, f func(K, V) bool) {
+	node, i := t.first()
+	t.ascend(node, i, &pivot, f)
+}
+
+// Descend calls f for every entry in descending key order, stopping early if
+// f returns false.
+// Time complexity: O(n).
+func (t *Tree[K, V]) Descend(f func(K, V) bool) {
+	node, i := t.last()
+	t.descend(node, i, nil, f)
+}
+
+// DescendRange calls f for every entry with low < key <= high in descending
+// key order, stopping early if f returns false.
+// Time complexity: O(log n + k) for k visited entries.
+func (t *Tree[K, V]) DescendRange(high, low K, f func(K, V) bool) {
+	node, i := t.floor(high)
+	t.descend(node, i, &low, f)
+}
+
+// DescendLessOrEqual calls f for every entry with key <= pivot in descending
+// key order, stopping early if f returns false.
+// Time complexity: O(log n + k) for k visited entries.
+func (t *Tree[K, V]) DescendLessOrEqual(pivot K, f func(K, V) bool) {
+	node, i := t.floor(pivot)
+	t.descend(node, i, nil, f)
+}
+
+// DescendGreaterThan calls f for every entry with key > pivot in descending
+// key order, stopping early if f returns false.
+// Time complexity: O(log n + k) for k visited entries.
+func (t *Tree[K, V]) DescendGreaterThan(pivot K, f func(K, V) bool) {
+	node, i := t.last()
+	t.descend(node, i, &pivot, f)
+}
+
+var _ container.ReverseIteratorWithKey[string, int] = (*Iterator[string, int])(nil)
+
+// position records where an Iterator sits relative to the tree's entries.
+type position byte
+
+const (
+	begin   position = iota // Before the first entry.
+	between                 // On an entry.
+	end                     // Past the last entry.
+)
+
+// Iterator is a stateful, bidirectional iterator over the tree's entries in
+// sorted order, satisfying container.ReverseIteratorWithKey.
+//
+// Iter corresponds to Iterator followed by repeated Next calls, and RIter to
+// ReverseIterator followed by repeated Prev calls. The iterator is invalidated
+// by any modification of the tree.
+type Iterator[K comparable, V any] struct {
+	tree     *Tree[K, V]
+	node     *Node[K, V]
+	entry    int
+	position position
+}
+
+// Iterator returns a stateful iterator positioned before the first entry.
+// Time complexity: O(1).
+func (t *Tree[K, V]) Iterator() *Iterator[K, V] {
+	return &Iterator[K, V]{tree: t, position: begin}
+}
+
+// ReverseIterator returns a stateful iterator positioned past the last entry,
+// ready for backward traversal with Prev.
+// Time complexity: O(1).
+func (t *Tree[K, V]) ReverseIterator() *Iterator[K, V] {
+	return &Iterator[K, V]{tree: t, position: end}
+}
+
+// Next moves the iterator to the next entry and returns true if there was one.
+// Time complexity: O(log n) worst case, amortized O(1).
+func (it *Iterator[K, V]) Next() bool {
+	switch it.position {
+	case end:
+		return false
+	case begin:
+		it.node, it.entry = it.tree.GetBeginNode(), 0
+	default:
+		it.node, it.entry = successor(it.node, it.entry)
+	}
+
+	if it.node == nil {
+		it.position = end
+
+		return false
+	}
+
+	it.position = between
+
+	return true
+}
+
+// Prev moves the iterator to the previous entry and returns true if there was one.
+// Time complexity: O(log n) worst case, amortized O(1).
+func (it *Iterator[K, V]) Prev() bool {
+	switch it.position {
+	case begin:
+		return false
+	case end:
+		it.node = it.tree.GetEndNode()
+		if it.node != nil {
+			it.entry = len(it.node.entries) - 1
+		}
+	default:
+		it.node, it.entry = predecessor(it.node, it.entry)
+	}
+
+	if it.node == nil {
+		it.position = begin
+
+		return false
+	}
+
+	it.position = between
+
+	return true
+}
+
+// Advance moves the iterator n entries forward, as if Next were called n
+// times, and reports whether it is positioned on an entry afterwards.
+// Advance(0) reports whether the iterator is currently on an entry.
+// Time complexity: O(n) amortized.
+func (it *Iterator[K, V]) Advance(n int) bool {
+	for range n {
+		if !it.Next() {
+			return false
+		}
+	}
+
+	return it.position == between
+}
+
+// Key returns the key of the current entry.
+// Only valid after Next, Prev, First, Last or Advance returned true.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Key() K {
+	return it.node.entries[it.entry].key
+}
+
+// Value returns the value of the current entry.
+// Only valid after Next, Prev, First, Last or Advance returned true.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Value() V {
+	return it.node.entries[it.entry].value
+}
+
+// Node returns the node holding the current entry, or nil if the iterator is
+// not on an entry.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Node() *Node[K, V] {
+	return it.node
+}
+
+// Begin resets the iterator to before the first entry.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Begin() {
+	it.node, it.entry = nil, 0
+	it.position = begin
+}
+
+// End moves the iterator past the last entry.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) End() {
+	it.node, it.entry = nil, 0
+	it.position = end
+}
+
+// First moves the iterator to the first entry and returns true if there was one.
+// Time complexity: O(log n).
+func (it *Iterator[K, V]) First() bool {
+	it.Begin()
+
+	return it.Next()
+}
+
+// Last moves the iterator to the last entry and returns true if there was one.
+// Time complexity: O(log n).
+func (it *Iterator[K, V]) Last() bool {
+	it.End()
+
+	return it.Prev()
+}
+
+// Diff compares two trees key by key and returns the keys present only in a,
+// the keys present only in b, and the keys present in both whose values
+// differ according to eq, each in sorted order.
+//
+// Both trees are walked in order simultaneously, merge-style, so a and b must
+// order keys identically; their comparators cannot be checked and are
+// assumed to match, with a's used for the walk.
+// Time complexity: O(n + m) for trees of n and m entries.
+func Diff[K comparable, V any](a, b *Tree[K, V], eq func(V, V) bool) (onlyA, onlyB, changed []K) {
+	na, ia := a.first()
+	nb, ib := b.first()
+
+	for na != nil && nb != nil {
+		ea, eb := na.entries[ia], nb.entries[ib]
+
+		switch c := a.cmp(ea.key, eb.key); {
+		case c < 0:
+			onlyA = append(onlyA, ea.key)
+			na, ia = successor(na, ia)
+		case c > 0:
+			onlyB = append(onlyB, eb.key)
+			nb, ib = successor(nb, ib)
+		default:
+			if !eq(ea.value, eb.value) {
+				changed = append(changed, ea.key)
+			}
+
+			na, ia = successor(na, ia)
+			nb, ib = successor(nb, ib)
+		}
+	}
+
+	for ; na != nil; na, ia = successor(na, ia) {
+		onlyA = append(onlyA, na.entries[ia].key)
+	}
+
+	for ; nb != nil; nb, ib = successor(nb, ib) {
+		onlyB = append(onlyB, nb.entries[ib].key)
+	}
+
+	return onlyA, onlyB, changed
+}
+
+// PrefixScan returns an iterator over the entries of t whose key starts with
+// prefix, in sorted order. An empty prefix yields every entry.
+//
+// It is a function rather than a method because it only applies to string
+// keys. The tree's comparator must order strings byte-wise (as New and
+// cmp.Compare do), so that all keys sharing a prefix are contiguous; the scan
+// seeks to the first key >= prefix and stops at the first key without it.
+// Time complexity: O(log n + k) for k matching entries.
+func PrefixScan[V any](t *Tree[string, V], prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		node, i := t.ceiling(prefix)
+		for node != nil {
+			e := node.entries[i]
+			if !strings.HasPrefix(e.key, prefix) || !yield(e.key, e.value) {
+				return
+			}
+
+			node, i = successor(node, i)
+		}
+	}
+}
+
+// EntryAt returns the i-th smallest key-value pair (0-based) in sorted order.
+// Returns zero values and false if i is out of range [0, Len()).
+// It is an alias of Select; thanks to the subtree sizes kept in each node it
+// runs in O(log n) rather than walking i entries.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) EntryAt(i int) (k K, v V, ok bool) {
+	return t.Select(i)
+}
+
 // String returns a string representation of the tree for debugging.
 func (t *Tree[K, V]) String() string {
 	if t.IsEmpty() {
@@ -403,13 +1004,58 @@ func (t *Tree[K, V]) String() string {
 	return sb.String()
 }
 
+// SetJSONFormat selects the encoding produced by MarshalJSON. It does not
+// affect UnmarshalJSON, which accepts both forms.
+func (t *Tree[K, V]) SetJSONFormat(format JSONFormat) {
+	t.format = format
+}
+
 // MarshalJSON implements the json.Marshaler interface. Time complexity: O(n).
+//
+// The encoding depends on the tree's JSONFormat. In the JSONArray format the
+// entries are written one by one in sorted order, without first collecting
+// them into a map.
 func (t *Tree[K, V]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(maps.Collect(t.Iter()))
+	if t.format != JSONArray {
+		return json.Marshal(maps.Collect(t.Iter()))
+	}
+
+	buf := []byte{'['}
+
+	for k, v := range t.Iter() {
+		data, err := json.Marshal(jsonEntry[K, V]{Key: k, Value: v})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(buf) > 1 {
+			buf = append(buf, ',')
+		}
+
+		buf = append(buf, data...)
+	}
+
+	return append(buf, ']'), nil
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface. Time complexity: O(m log m).
+//
+// The JSON object carries only the entries, so the receiver must already be
+// constructed with its order and comparator, which are kept; entries are
+// ordered by that comparator. Returns ErrUninitialized for a zero-value tree.
+//
+// Both encodings of JSONFormat are accepted. In the array form a repeated key
+// keeps its last value, and entries already in strictly ascending key order
+// are bulk-loaded in O(m).
 func (t *Tree[K, V]) UnmarshalJSON(data []byte) error {
+	if t.m < 3 || t.cmp == nil {
+		return ErrUninitialized
+	}
+
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		return t.unmarshalArray(data)
+	}
+
 	var elems map[K]V
 	if err := json.Unmarshal(data, &elems); err != nil {
 		return err
@@ -424,6 +1070,107 @@ func (t *Tree[K, V]) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// unmarshalArray replaces the tree's entries with those of the JSONArray
+// encoding in data.
+func (t *Tree[K, V]) unmarshalArray(data []byte) error {
+	var elems []jsonEntry[K, V]
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+
+	sorted := true
+
+	for i := 1; i < len(elems) && sorted; i++ {
+		sorted = t.cmp(elems[i-1].Key, elems[i].Key) < 0
+	}
+
+	if !sorted {
+		t.Clear()
+
+		for _, e := range elems {
+			t.Put(e.Key, e.Value)
+		}
+
+		return nil
+	}
+
+	entries := make([]*entry[K, V], len(elems))
+	for i, e := range elems {
+		entries[i] = &entry[K, V]{key: e.Key, value: e.Value}
+	}
+
+	t.build(entries)
+
+	return nil
+}
+
+// Select returns the i-th smallest key-value pair (0-based) in sorted order.
+// Returns zero values and false if i is out of range [0, Len()).
+// Time complexity: O(log n).
+func (t *Tree[K, V]) Select(i int) (k K, v V, ok bool) {
+	if i < 0 || i >= t.len {
+		return k, v, false
+	}
+
+	node := t.root
+
+	for !node.isLeaf() {
+		j := 0
+		for ; j < len(node.entries); j++ {
+			cs := node.children[j].size
+			if i < cs {
+				break
+			}
+
+			i -= cs
+
+			if i == 0 {
+				e := node.entries[j]
+
+				return e.key, e.value, true
+			}
+
+			i--
+		}
+
+		node = node.children[j]
+	}
+
+	e := node.entries[i]
+
+	return e.key, e.value, true
+}
+
+// Rank returns the number of keys in the tree strictly less than the given key.
+// The key does not need to be present in the tree.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) Rank(key K) int {
+	rank := 0
+
+	for node := t.root; node != nil; {
+		index, found := t.search(node, key)
+		rank += index
+
+		if node.isLeaf() {
+			break
+		}
+
+		for _, c := range node.children[:index] {
+			rank += c.size
+		}
+
+		if found {
+			rank += node.children[index].size
+
+			break
+		}
+
+		node = node.children[index]
+	}
+
+	return rank
+}
+
 // isLeaf checks if a node is a leaf (has no children).
 func (n *Node[K, V]) isLeaf() bool {
 	return len(n.children) == 0
@@ -452,6 +1199,110 @@ func (t *Tree[K, V]) lookup(key K) (*Node[K, V], int) {
 	}
 }
 
+// first returns the position of the smallest entry, or (nil, 0) if the tree
+// is empty.
+func (t *Tree[K, V]) first() (*Node[K, V], int) {
+	if t.len == 0 {
+		return nil, 0
+	}
+
+	return getMinNode(t.root), 0
+}
+
+// last returns the position of the largest entry, or (nil, 0) if the tree
+// is empty.
+func (t *Tree[K, V]) last() (*Node[K, V], int) {
+	if t.len == 0 {
+		return nil, 0
+	}
+
+	n := getMaxNode(t.root)
+
+	return n, len(n.entries) - 1
+}
+
+// floor finds the position of the largest entry with a key less than or
+// equal to the given key. Returns (nil, 0) if there is none.
+func (t *Tree[K, V]) floor(key K) (*Node[K, V], int) {
+	var (
+		best  *Node[K, V]
+		bestI int
+	)
+
+	for node := t.root; node != nil; {
+		index, found := t.search(node, key)
+		if found {
+			return node, index
+		}
+
+		if index > 0 {
+			best, bestI = node, index-1
+		}
+
+		if node.isLeaf() {
+			break
+		}
+
+		node = node.children[index]
+	}
+
+	return best, bestI
+}
+
+// ascend walks forward from node.entries[i], calling f until it returns false
+// or a key not less than the optional stop key is reached.
+func (t *Tree[K, V]) ascend(node *Node[K, V], i int, stop *K, f func(K, V) bool) {
+	for node != nil {
+		e := node.entries[i]
+		if stop != nil && t.cmp(e.key, *stop) >= 0 || !f(e.key, e.value) {
+			return
+		}
+
+		node, i = successor(node, i)
+	}
+}
+
+// descend walks backward from node.entries[i], calling f until it returns
+// false or a key not greater than the optional stop key is reached.
+func (t *Tree[K, V]) descend(node *Node[K, V], i int, stop *K, f func(K, V) bool) {
+	for node != nil {
+		e := node.entries[i]
+		if stop != nil && t.cmp(e.key, *stop) <= 0 || !f(e.key, e.value) {
+			return
+		}
+
+		node, i = predecessor(node, i)
+	}
+}
+
+// ceiling finds the position of the smallest entry with a key greater than or
+// equal to the given key. Returns (nil, 0) if there is none.
+func (t *Tree[K, V]) ceiling(key K) (*Node[K, V], int) {
+	var (
+		best  *Node[K, V]
+		bestI int
+	)
+
+	for node := t.root; node != nil; {
+		index, found := t.search(node, key)
+		if found {
+			return node, index
+		}
+
+		if index < len(node.entries) {
+			best, bestI = node, index
+		}
+
+		if node.isLeaf() {
+			break
+		}
+
+		node = node.children[index]
+	}
+
+	return best, bestI
+}
+
 // search performs a binary search for a key within a single node's entries.
 func (t *Tree[K, V]) search(node *Node[K, V], key K) (index int, found bool) {
 	return slices.BinarySearchFunc(node.entries, key, func(e *entry[K, V], k K) int {
@@ -476,12 +1327,23 @@ func (t *Tree[K, V]) insertIntoLeaf(node *Node[K, V], e *entry[K, V]) bool {
 		return false
 	}
 
-	node.entries = slices.Insert(node.entries, index, e)
-	t.split(node)
+	t.insertAt(node, index, e)
 
 	return true
 }
 
+// insertAt inserts an entry into a leaf at the given index, updating subtree
+// sizes along the path to the root and splitting if the leaf overflows.
+func (t *Tree[K, V]) insertAt(node *Node[K, V], index int, e *entry[K, V]) {
+	node.entries = slices.Insert(node.entries, index, e)
+
+	for n := node; n != nil; n = n.parent {
+		n.size++
+	}
+
+	t.split(node)
+}
+
 func (t *Tree[K, V]) insertIntoInternal(node *Node[K, V], e *entry[K, V]) bool {
 	index, found := t.search(node, e.key)
 	if found {
@@ -533,6 +1395,9 @@ func (t *Tree[K, V]) splitNonRoot(n *Node[K, V]) {
 		n.children = n.children[:mid+1]
 	}
 
+	n.recount()
+	r.recount()
+
 	// Insert right sibling into parent's children
 	p.children = slices.Insert(p.children, pi+1, r)
 
@@ -553,9 +1418,13 @@ func (t *Tree[K, V]) splitRoot() {
 		setParent(r.children, r)
 	}
 
+	l.recount()
+	r.recount()
+
 	nr := &Node[K, V]{
 		entries:  []*entry[K, V]{med},
 		children: []*Node[K, V]{l, r},
+		size:     t.root.size,
 	}
 
 	l.parent = nr
@@ -575,6 +1444,11 @@ func (t *Tree[K, V]) delete(n *Node[K, V], i int) {
 
 	// Delete entry from the (now guaranteed to be leaf) node.
 	n.entries = slices.Delete(n.entries, i, i+1)
+
+	for p := n; p != nil; p = p.parent {
+		p.size--
+	}
+
 	t.rebalance(n)
 }
 
@@ -662,6 +1536,9 @@ func (t *Tree[K, V]) borrowFromSibling(n, s *Node[K, V], ni int) {
 			s.children = slices.Delete(s.children, 0, 1)
 		}
 	}
+
+	n.recount()
+	s.recount()
 }
 
 // mergeWithSibling merges two adjacent nodes.
@@ -677,6 +1554,8 @@ func (t *Tree[K, V]) mergeWithSibling(l, r *Node[K, V], li int) {
 		setParent(l.children, l)
 	}
 
+	l.recount()
+
 	// Remove separator and right node from parent
 	p.entries = slices.Delete(p.entries, li, li+1)
 	p.children = slices.Delete(p.children, li+1, li+2)
@@ -687,9 +1566,92 @@ func (t *Tree[K, V]) mergeWithSibling(l, r *Node[K, V], li int) {
 	}
 }
 
+// recount recomputes the cached subtree entry count from the node's own
+// entries and its children's cached counts.
+func (n *Node[K, V]) recount() {
+	n.size = len(n.entries)
+	for _, c := range n.children {
+		n.size += c.size
+	}
+}
+
+// build replaces the contents of the tree with entries, which must be sorted
+// and free of duplicates, packing each node as full as the order allows.
+func (t *Tree[K, V]) build(entries []*entry[K, V]) {
+	t.len = len(entries)
+	t.root = nil
+
+	if len(entries) == 0 {
+		return
+	}
+
+	h := 0
+	for t.capacity(h) < len(entries) {
+		h++
+	}
+
+	t.root = t.buildNode(entries, h, nil)
+}
+
+// buildNode builds a subtree of height h (0 for a leaf) holding entries.
+// Children are given as many entries as they can hold, spread evenly, so
+// every node keeps at least minEntries.
+func (t *Tree[K, V]) buildNode(entries []*entry[K, V], h int, parent *Node[K, V]) *Node[K, V] {
+	n := &Node[K, V]{parent: parent, size: len(entries)}
+
+	if h == 0 {
+		n.entries = slices.Clone(entries)
+
+		return n
+	}
+
+	childCap := t.capacity(h - 1)
+	c := max((len(entries)+1+childCap)/(childCap+1), 2)
+	per, extra := (len(entries)-c+1)/c, (len(entries)-c+1)%c
+
+	n.entries = make([]*entry[K, V], 0, c-1)
+	n.children = make([]*Node[K, V], 0, c)
+
+	lo := 0
+
+	for i := range c {
+		sz := per
+		if i < extra {
+			sz++
+		}
+
+		n.children = append(n.children, t.buildNode(entries[lo:lo+sz], h-1, n))
+		lo += sz
+
+		if i < c-1 {
+			n.entries = append(n.entries, entries[lo])
+			lo++
+		}
+	}
+
+	return n
+}
+
+// capacity returns the maximum number of entries in a subtree of height h
+// (0 for a leaf), which is m^(h+1) - 1.
+func (t *Tree[K, V]) capacity(h int) int {
+	c := t.maxEntries()
+	for range h {
+		c = t.m*c + t.maxEntries()
+	}
+
+	return c
+}
+
 func (t *Tree[K, V]) maxEntries() int { return t.m - 1 }
 func (t *Tree[K, V]) minEntries() int { return (t.m+1)/2 - 1 }
-func (t *Tree[K, V]) middle() int     { return (t.m - 1) / 2 }
+func (t *Tree[K, V]) middle() int {
+	if t.bias == RightBias {
+		return t.m / 2
+	}
+
+	return (t.m - 1) / 2
+}
 
 func setParent[K comparable, V any](nodes []*Node[K, V], parent *Node[K, V]) {
 	for _, n := range nodes {
@@ -708,6 +1670,54 @@ func findChildIndex[K comparable, V any](parent, children *Node[K, V]) int {
 	return notFound
 }
 
+// successor returns the position of the entry following node.entries[i] in
+// sorted order, or (nil, 0) if it is the last entry.
+func successor[K comparable, V any](node *Node[K, V], i int) (*Node[K, V], int) {
+	if !node.isLeaf() {
+		return getMinNode(node.children[i+1]), 0
+	}
+
+	if i+1 < len(node.entries) {
+		return node, i + 1
+	}
+
+	for node.parent != nil {
+		ci := findChildIndex(node.parent, node)
+		node = node.parent
+
+		if ci < len(node.entries) {
+			return node, ci
+		}
+	}
+
+	return nil, 0
+}
+
+// predecessor returns the position of the entry preceding node.entries[i] in
+// sorted order, or (nil, 0) if it is the first entry.
+func predecessor[K comparable, V any](node *Node[K, V], i int) (*Node[K, V], int) {
+	if !node.isLeaf() {
+		n := getMaxNode(node.children[i])
+
+		return n, len(n.entries) - 1
+	}
+
+	if i > 0 {
+		return node, i - 1
+	}
+
+	for node.parent != nil {
+		ci := findChildIndex(node.parent, node)
+		node = node.parent
+
+		if ci > 0 {
+			return node, ci - 1
+		}
+	}
+
+	return nil, 0
+}
+
 func getMinNode[K comparable, V any](node *Node[K, V]) *Node[K, V] {
 	if node == nil {
 		return nil
@@ -737,7 +1747,7 @@ func cloneNode[K comparable, V any](node *Node[K, V], parent *Node[K, V]) *Node[
 		return nil
 	}
 
-	newNode := &Node[K, V]{parent: parent}
+	newNode := &Node[K, V]{parent: parent, size: node.size}
 	newNode.entries = make([]*entry[K, V], len(node.entries))
 
 	for i, e := range node.entries {
@@ -754,6 +1764,28 @@ func cloneNode[K comparable, V any](node *Node[K, V], parent *Node[K, V]) *Node[
 	return newNode
 }
 
+// appendEntries appends the entries of the subtree rooted at n to dst in
+// sorted order.
+func appendEntries[K comparable, V any](dst []*entry[K, V], n *Node[K, V]) []*entry[K, V] {
+	if n == nil {
+		return dst
+	}
+
+	for i, e := range n.entries {
+		if !n.isLeaf() {
+			dst = appendEntries(dst, n.children[i])
+		}
+
+		dst = append(dst, e)
+	}
+
+	if !n.isLeaf() {
+		dst = appendEntries(dst, n.children[len(n.children)-1])
+	}
+
+	return dst
+}
+
 // inorder traversal for the iterator.
 func inorder[K comparable, V any](n *Node[K, V], yield func(K, V) bool) bool {
 	if n == nil {
@@ -206,3 +206,49 @@ func BenchmarkBTreeDelete100000(b *testing.B) {
 	b.StartTimer()
 	benchmarkDelete(b, tree, keys)
 }
+
+func benchmarkDeleteRange(b *testing.B, size, batch int, deleteMany bool) {
+	b.StopTimer()
+
+	keys := testutil.GeneratePermutedInts(size)
+
+	batchKeys := make([]int, batch)
+	for i := range batchKeys {
+		batchKeys[i] = size/4 + i
+	}
+
+	for range b.N {
+		b.StopTimer()
+
+		tree := btree.New[int, struct{}](32)
+		for _, key := range keys {
+			tree.Put(key, struct{}{})
+		}
+
+		b.StartTimer()
+
+		if deleteMany {
+			tree.DeleteMany(batchKeys)
+		} else {
+			for _, key := range batchKeys {
+				tree.Delete(key)
+			}
+		}
+	}
+}
+
+func BenchmarkBTreeDeleteRange10000(b *testing.B) {
+	benchmarkDeleteRange(b, 10000, 5000, false)
+}
+
+func BenchmarkBTreeDeleteManyRange10000(b *testing.B) {
+	benchmarkDeleteRange(b, 10000, 5000, true)
+}
+
+func BenchmarkBTreeDeleteRange100000(b *testing.B) {
+	benchmarkDeleteRange(b, 100000, 50000, false)
+}
+
+func BenchmarkBTreeDeleteManyRange100000(b *testing.B) {
+	benchmarkDeleteRange(b, 100000, 50000, true)
+}
@@ -1,9 +1,14 @@
 package btree
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
 	"slices"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -1077,6 +1082,116 @@ func TestBTreeSerialization(t *testing.T) {
 	}
 }
 
+func TestBTreeUnmarshalJSONKeepsComparator(t *testing.T) {
+	tree := NewWith[int, string](4, func(a, b int) int { return cmp.Compare(b, a) })
+
+	if err := json.Unmarshal([]byte(`{"1":"a","3":"c","2":"b","5":"e","4":"d"}`), tree); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []int{5, 4, 3, 2, 1}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := tree.MaxChildren(), 4; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	assertBTreeInvariants(t, tree)
+
+	var zero Tree[int, string]
+	if err := json.Unmarshal([]byte(`{"1":"a"}`), &zero); !errors.Is(err, ErrUninitialized) {
+		t.Errorf("Got error %v expected %v", err, ErrUninitialized)
+	}
+
+	if actualValue := zero.Len(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+}
+
+func TestBTreeJSONArrayFormat(t *testing.T) {
+	tree := New[int, string](3)
+	for _, k := range []int{3, 1, 2} {
+		tree.Put(k, fmt.Sprint(k))
+	}
+
+	if data, err := json.Marshal(tree); err != nil || string(data) != `{"1":"1","2":"2","3":"3"}` {
+		t.Errorf("Got %s,%v expected the object form by default", data, err)
+	}
+
+	tree.SetJSONFormat(JSONArray)
+
+	data, err := json.Marshal(tree)
+	if expected := `[{"key":1,"value":"1"},{"key":2,"value":"2"},{"key":3,"value":"3"}]`; err != nil || string(data) != expected {
+		t.Errorf("Got %s,%v expected %s", data, err, expected)
+	}
+
+	// The format is kept by Clone, and an empty tree encodes as an empty array.
+	clone := tree.Clone().(*Tree[int, string])
+	clone.Clear()
+
+	if data, err := json.Marshal(clone); err != nil || string(data) != `[]` {
+		t.Errorf("Got %s,%v expected %s", data, err, `[]`)
+	}
+
+	// UnmarshalJSON accepts both forms, whatever the receiver's format.
+	loaded := New[int, string](4)
+	if err := json.Unmarshal([]byte(`[]`), loaded); err != nil || !loaded.IsEmpty() {
+		t.Errorf("Got %v,%v expected an empty tree", loaded.Keys(), err)
+	}
+
+	if err := json.Unmarshal([]byte(` [{"key":5,"value":"a"},{"key":2,"value":"b"},{"key":5,"value":"c"}]`), loaded); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if actualValue, expectedValue := loaded.Values(), []string{"b", "c"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	tree.SetJSONFormat(JSONObject)
+
+	if err := json.Unmarshal([]byte(`{"7":"x"}`), tree); err != nil || !slices.Equal(tree.Keys(), []int{7}) {
+		t.Errorf("Got %v,%v expected %v", tree.Keys(), err, []int{7})
+	}
+
+	// Keys that cannot be JSON object keys round-trip through the array form.
+	type point struct{ X, Y int }
+
+	byXY := func(a, b point) int {
+		return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+	}
+
+	points := NewWith[point, int](3, byXY)
+	for i := range 200 {
+		points.Put(point{i % 7, i}, i)
+	}
+
+	if _, err := json.Marshal(points); err == nil {
+		t.Errorf("Expected an error encoding struct keys as an object")
+	}
+
+	points.SetJSONFormat(JSONArray)
+
+	if data, err = json.Marshal(points); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	decoded := NewWith[point, int](5, byXY)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	assertBTreeInvariants(t, decoded)
+
+	if !slices.Equal(decoded.Keys(), points.Keys()) || !slices.Equal(decoded.Values(), points.Values()) {
+		t.Errorf("Array round trip changed the entries")
+	}
+
+	if err := json.Unmarshal([]byte(`[{"key":"x"}]`), decoded); err == nil {
+		t.Errorf("Expected an error for a mistyped key")
+	}
+}
+
 func TestBTreeString(t *testing.T) {
 	c := New[string, int](3)
 	c.Put("a", 1)
@@ -1085,3 +1200,996 @@ func TestBTreeString(t *testing.T) {
 		t.Errorf("String should start with container name")
 	}
 }
+
+func TestBTreeReset(t *testing.T) {
+	tree := New[int, string](3)
+	for i := range 10 {
+		tree.Put(i, "v")
+	}
+
+	tree.Reset()
+	assertValidTree(t, tree, 0)
+
+	if actualValue := tree.Root(); actualValue == nil {
+		t.Fatalf("Got nil root, expected an empty root node")
+	}
+
+	root := tree.Root()
+	assertValidTreeNode(t, root, 0, 0, []int{}, false)
+
+	if actualValue := tree.Height(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	if actualValue := tree.GetBeginNode(); actualValue != nil {
+		t.Errorf("Got %v expected %v", actualValue, nil)
+	}
+
+	if _, _, ok := tree.Begin(); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+
+	tree.Put(2, "b")
+	tree.Put(1, "a")
+
+	if actualValue := tree.Root(); actualValue != root {
+		t.Errorf("Root node was reallocated after Reset")
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []int{1, 2}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	empty := New[int, string](3)
+	empty.Reset()
+	empty.Put(1, "a")
+
+	if actualValue, found := empty.Get(1); actualValue != "a" || !found {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, found, "a", true)
+	}
+}
+
+// assertSubtreeSizes verifies the cached subtree entry counts against a brute-force count.
+func assertSubtreeSizes[K comparable, V any](t *testing.T, n *Node[K, V]) int {
+	t.Helper()
+
+	if n == nil {
+		return 0
+	}
+
+	count := len(n.entries)
+	for _, c := range n.children {
+		count += assertSubtreeSizes(t, c)
+	}
+
+	if n.size != count {
+		t.Fatalf("Got %v expected %v for subtree size of node %v", n.size, count, n)
+	}
+
+	return count
+}
+
+func TestBTreeSelectAndRank(t *testing.T) {
+	for _, order := range []int{3, 4, 5, 8} {
+		tree := New[int, int](order)
+		present := map[int]bool{}
+		rng := rand.New(rand.NewPCG(uint64(order), 0))
+
+		for range 2000 {
+			k := rng.IntN(300)
+			if rng.IntN(3) == 0 {
+				tree.Delete(k)
+				delete(present, k)
+			} else {
+				tree.Put(k, k*10)
+				present[k] = true
+			}
+
+			assertSubtreeSizes(t, tree.Root())
+		}
+
+		keys := tree.Keys()
+
+		for i, k := range keys {
+			if ak, av, ok := tree.Select(i); !ok || ak != k || av != k*10 {
+				t.Errorf("Select(%v): got %v,%v,%v expected %v,%v,%v", i, ak, av, ok, k, k*10, true)
+			}
+		}
+
+		if _, _, ok := tree.Select(len(keys)); ok {
+			t.Errorf("Select(%v) should be out of range", len(keys))
+		}
+
+		if _, _, ok := tree.Select(-1); ok {
+			t.Errorf("Select(-1) should be out of range")
+		}
+
+		for probe := -1; probe <= 301; probe++ {
+			expected := 0
+
+			for k := range present {
+				if k < probe {
+					expected++
+				}
+			}
+
+			if actualValue := tree.Rank(probe); actualValue != expected {
+				t.Errorf("Rank(%v): got %v expected %v", probe, actualValue, expected)
+			}
+		}
+	}
+}
+
+func TestBTreeUpsert(t *testing.T) {
+	tree := New[string, int](3)
+	merges := 0
+	sum := func(old, new int) int {
+		merges++
+
+		return old + new
+	}
+
+	words := strings.Fields("a b c a d b a e f g a h")
+	for _, w := range words {
+		tree.Upsert(w, 1, sum)
+		assertSubtreeSizes(t, tree.Root())
+	}
+
+	if actualValue, expectedValue := merges, len(words)-8; actualValue != expectedValue {
+		t.Errorf("Got %v merges expected %v", actualValue, expectedValue)
+	}
+
+	assertValidTree(t, tree, 8)
+
+	expected := map[string]int{"a": 4, "b": 2, "c": 1, "d": 1, "e": 1, "f": 1, "g": 1, "h": 1}
+	for k, v := range expected {
+		if actualValue, ok := tree.Get(k); !ok || actualValue != v {
+			t.Errorf("Get(%v): got %v,%v expected %v,%v", k, actualValue, ok, v, true)
+		}
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []string{"a", "b", "c", "d", "e", "f", "g", "h"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestBTreeIteratorAndAdvance(t *testing.T) {
+	for _, order := range []int{3, 4, 5} {
+		tree := New[int, int](order)
+
+		it := tree.Iterator()
+		if it.Next() || it.Prev() || it.First() || it.Last() || it.Advance(1) {
+			t.Errorf("Iterator on empty tree should not move")
+		}
+
+		for i := range 100 {
+			tree.Put(i*7%100, i*7%100*10)
+		}
+
+		it = tree.Iterator()
+
+		var keys []int
+		for it.Next() {
+			if actualValue, expectedValue := it.Value(), it.Key()*10; actualValue != expectedValue {
+				t.Errorf("Got %v expected %v", actualValue, expectedValue)
+			}
+
+			keys = append(keys, it.Key())
+		}
+
+		if actualValue, expectedValue := keys, tree.Keys(); !slices.Equal(actualValue, expectedValue) {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+
+		keys = keys[:0]
+		for it.Prev() {
+			keys = append(keys, it.Key())
+		}
+
+		reversed := tree.Keys()
+		slices.Reverse(reversed)
+
+		if actualValue, expectedValue := keys, reversed; !slices.Equal(actualValue, expectedValue) {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+
+		// Rows 20..24 via Advance.
+		it.Begin()
+
+		if !it.Advance(21) || it.Key() != 20 {
+			t.Errorf("Advance(21): got %v expected %v", it.Key(), 20)
+		}
+
+		for want := 21; want < 25; want++ {
+			if !it.Advance(1) || it.Key() != want {
+				t.Errorf("Advance(1): got %v expected %v", it.Key(), want)
+			}
+		}
+
+		if it.Advance(100) {
+			t.Errorf("Advance past the end should report false")
+		}
+
+		rit := tree.ReverseIterator()
+		if !rit.Prev() || rit.Key() != 99 || !rit.Last() || rit.Key() != 99 || !rit.First() || rit.Key() != 0 {
+			t.Errorf("ReverseIterator/First/Last should reposition the iterator")
+		}
+
+		for i := -1; i <= 100; i++ {
+			k, v, ok := tree.EntryAt(i)
+			if expectedOk := i >= 0 && i < 100; ok != expectedOk || (ok && (k != i || v != i*10)) {
+				t.Errorf("EntryAt(%v): got %v,%v,%v", i, k, v, ok)
+			}
+		}
+	}
+}
+
+func TestBTreeLevels(t *testing.T) {
+	tree := New[int, int](3)
+
+	if levels := tree.Levels(); levels != nil {
+		t.Errorf("Got %v expected %v", levels, nil)
+	}
+
+	for i := 1; i <= 7; i++ {
+		tree.Put(i, i)
+	}
+
+	levels := tree.Levels()
+	if actualValue, expectedValue := len(levels), tree.Height(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// Order 3 with keys 1..7 yields a perfect tree: [4] / [2] [6] / [1] [3] [5] [7].
+	expected := [][][]int{{{4}}, {{2}, {6}}, {{1}, {3}, {5}, {7}}}
+	for depth, level := range levels {
+		if actualValue, expectedValue := len(level), len(expected[depth]); actualValue != expectedValue {
+			t.Fatalf("Level %v: got %v nodes expected %v", depth, actualValue, expectedValue)
+		}
+
+		for i, n := range level {
+			var keys []int
+			for _, e := range n.Entries() {
+				keys = append(keys, e.Key())
+			}
+
+			if !slices.Equal(keys, expected[depth][i]) {
+				t.Errorf("Level %v node %v: got %v expected %v", depth, i, keys, expected[depth][i])
+			}
+		}
+	}
+
+	total := 0
+
+	tree.WalkBFS(func(depth int, n *Node[int, int]) {
+		if depth > 0 && n.Parent() == nil {
+			t.Errorf("Non-root node at depth %v has no parent", depth)
+		}
+
+		total += len(n.Entries())
+	})
+
+	if total != tree.Len() {
+		t.Errorf("Got %v expected %v", total, tree.Len())
+	}
+}
+
+func TestBTreePrefixScan(t *testing.T) {
+	tree := New[string, int](3)
+
+	for range PrefixScan(tree, "a") {
+		t.Errorf("Shouldn't iterate on empty tree")
+	}
+
+	words := strings.Fields("app apple application apply apt banana band bandana can")
+	for i, w := range words {
+		tree.Put(w, i)
+	}
+
+	collect := func(prefix string) []string {
+		var keys []string
+
+		for k, v := range PrefixScan(tree, prefix) {
+			if v != slices.Index(words, k) {
+				t.Errorf("Got %v expected %v for %v", v, slices.Index(words, k), k)
+			}
+
+			keys = append(keys, k)
+		}
+
+		return keys
+	}
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"app", []string{"app", "apple", "application", "apply"}},
+		{"appl", []string{"apple", "application", "apply"}},
+		{"ban", []string{"banana", "band", "bandana"}},
+		{"band", []string{"band", "bandana"}},
+		{"c", []string{"can"}},
+		{"b", []string{"banana", "band", "bandana"}},
+		{"aa", nil},
+		{"d", nil},
+		{"", words},
+	}
+
+	for _, test := range tests {
+		if actualValue := collect(test.prefix); !slices.Equal(actualValue, test.expected) {
+			t.Errorf("PrefixScan(%q): got %v expected %v", test.prefix, actualValue, test.expected)
+		}
+	}
+
+	for range PrefixScan(tree, "app") {
+		break
+	}
+}
+
+func TestBTreeMapValues(t *testing.T) {
+	tree := New[int, int](4)
+	tree.MapValues(func(int, int) int {
+		t.Errorf("MapValues should not call f on an empty tree")
+
+		return 0
+	})
+
+	for i := range 50 {
+		tree.Put(i, i)
+	}
+
+	height := tree.Height()
+
+	var visited []int
+
+	tree.MapValues(func(k, v int) int {
+		visited = append(visited, k)
+
+		return v*2 + k
+	})
+
+	if actualValue, expectedValue := visited, tree.Keys(); !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	for k, v := range tree.Iter() {
+		if v != 3*k {
+			t.Errorf("Got %v expected %v for key %v", v, 3*k, k)
+		}
+	}
+
+	if tree.Height() != height || tree.Len() != 50 {
+		t.Errorf("MapValues should not change the tree shape")
+	}
+}
+
+// assertBTreeInvariants checks entry counts, fan-out, key order, parent links
+// and uniform leaf depth for every node of the tree.
+func assertBTreeInvariants[K comparable, V any](t *testing.T, tree *Tree[K, V]) {
+	t.Helper()
+
+	leafDepth := -1
+
+	tree.WalkBFS(func(depth int, n *Node[K, V]) {
+		if n != tree.root && (len(n.entries) < tree.minEntries() || len(n.entries) > tree.maxEntries()) {
+			t.Fatalf("Node %v has %v entries, expected [%v, %v]", n, len(n.entries), tree.minEntries(), tree.maxEntries())
+		}
+
+		for i := 1; i < len(n.entries); i++ {
+			if tree.cmp(n.entries[i-1].key, n.entries[i].key) >= 0 {
+				t.Fatalf("Node %v keys out of order", n)
+			}
+		}
+
+		if n.isLeaf() {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if depth != leafDepth {
+				t.Fatalf("Leaf %v at depth %v, expected %v", n, depth, leafDepth)
+			}
+
+			return
+		}
+
+		if len(n.children) != len(n.entries)+1 {
+			t.Fatalf("Node %v has %v children for %v entries", n, len(n.children), len(n.entries))
+		}
+
+		for _, c := range n.children {
+			if c.parent != n {
+				t.Fatalf("Child %v has wrong parent", c)
+			}
+		}
+	})
+
+	assertSubtreeSizes(t, tree.root)
+}
+
+func TestBTreeSplitBias(t *testing.T) {
+	countNodes := func(tree *Tree[int, int]) int {
+		nodes := 0
+
+		tree.WalkBFS(func(int, *Node[int, int]) { nodes++ })
+
+		return nodes
+	}
+
+	for _, order := range []int{3, 4, 5, 6} {
+		left := NewWithBias[int, int](order, cmp.Compare[int], LeftBias)
+		right := NewWithBias[int, int](order, cmp.Compare[int], RightBias)
+
+		for i := range 500 {
+			left.Put(i, i)
+			right.Put(i, i)
+		}
+
+		assertBTreeInvariants(t, left)
+		assertBTreeInvariants(t, right)
+
+		ln, rn := countNodes(left), countNodes(right)
+		if order%2 == 1 && ln != rn {
+			t.Errorf("Order %v: odd orders should not depend on bias, got %v and %v nodes", order, ln, rn)
+		}
+
+		if order%2 == 0 && rn >= ln {
+			t.Errorf("Order %v: right bias should use fewer nodes for ascending keys, got %v >= %v", order, rn, ln)
+		}
+
+		// Random deletes and inserts keep both trees valid.
+		rng := rand.New(rand.NewPCG(uint64(order), 1))
+		for range 1000 {
+			k := rng.IntN(600)
+			if rng.IntN(2) == 0 {
+				right.Delete(k)
+			} else {
+				right.Put(k, k)
+			}
+		}
+
+		assertBTreeInvariants(t, right)
+
+		if clone := right.Clone().(*Tree[int, int]); clone.bias != RightBias {
+			t.Errorf("Clone should preserve the split bias")
+		}
+	}
+}
+
+func TestBTreeGetRef(t *testing.T) {
+	type stats struct {
+		hits  int
+		bytes [64]byte
+	}
+
+	tree := New[string, stats](3)
+
+	if ref, ok := tree.GetRef("a"); ref != nil || ok {
+		t.Errorf("Got %v,%v expected %v,%v", ref, ok, nil, false)
+	}
+
+	for _, k := range strings.Fields("a b c d e f g") {
+		tree.Put(k, stats{})
+	}
+
+	for range 3 {
+		ref, ok := tree.GetRef("d")
+		if !ok {
+			t.Fatalf("GetRef should find an existing key")
+		}
+
+		ref.hits++
+	}
+
+	// Splits move entry pointers between nodes but keep them alive.
+	ref, _ := tree.GetRef("a")
+	for _, k := range strings.Fields("h i j k l m n") {
+		tree.Put(k, stats{})
+	}
+
+	ref.hits = 7
+
+	if v, _ := tree.Get("d"); v.hits != 3 {
+		t.Errorf("Got %v expected %v", v.hits, 3)
+	}
+
+	if v, _ := tree.Get("a"); v.hits != 7 {
+		t.Errorf("Got %v expected %v", v.hits, 7)
+	}
+}
+
+func TestBTreePopMinMax(t *testing.T) {
+	tree := New[int, string](3)
+	for i := range 10 {
+		tree.Put(i, strings.Repeat("x", i))
+	}
+
+	if k, v, found := tree.PopMin(); k != 0 || v != "" || !found {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", k, v, found, 0, "", true)
+	}
+
+	if k, v, found := tree.PopMax(); k != 9 || v != "xxxxxxxxx" || !found {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", k, v, found, 9, "xxxxxxxxx", true)
+	}
+
+	assertBTreeInvariants(t, tree)
+	assertValidTree(t, tree, 8)
+}
+
+func TestBTreeDeleteMany(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for _, order := range []int{3, 4, 5, 8, 32} {
+		for _, size := range []int{0, 1, 2, 10, 100, 1000} {
+			for _, batch := range []int{0, 1, 5, size / 4, size, size + 10} {
+				tree := New[int, int](order)
+				expected := map[int]int{}
+
+				for _, k := range rng.Perm(size) {
+					tree.Put(k, k*10)
+					expected[k] = k * 10
+				}
+
+				// A clustered range plus absent and repeated keys.
+				start := rng.IntN(size + 1)
+
+				var keys []int
+				for k := start; k < start+batch; k++ {
+					keys = append(keys, k, k, -k-1)
+				}
+
+				rng.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+
+				removed := 0
+
+				for _, k := range keys {
+					if _, ok := expected[k]; ok {
+						delete(expected, k)
+						removed++
+					}
+				}
+
+				if actualValue := tree.DeleteMany(keys); actualValue != removed {
+					t.Errorf("order=%v size=%v batch=%v: got %v removed expected %v", order, size, batch, actualValue, removed)
+				}
+
+				if actualValue, expectedValue := tree.Len(), len(expected); actualValue != expectedValue {
+					t.Errorf("order=%v size=%v batch=%v: got len %v expected %v", order, size, batch, actualValue, expectedValue)
+				}
+
+				for k, v := range tree.Iter() {
+					if ev, ok := expected[k]; !ok || ev != v {
+						t.Errorf("order=%v size=%v batch=%v: unexpected entry %v=%v", order, size, batch, k, v)
+					}
+				}
+
+				assertBTreeInvariants(t, tree)
+
+				// The tree must remain usable after a rebuild.
+				for k := range 50 {
+					tree.Put(-k-1, k)
+				}
+
+				for k := range 50 {
+					tree.Delete(-k - 1)
+				}
+
+				assertBTreeInvariants(t, tree)
+			}
+		}
+	}
+}
+
+func TestBTreeDiff(t *testing.T) {
+	eq := func(a, b string) bool { return a == b }
+
+	a, b := New[int, string](3), New[int, string](4)
+
+	if onlyA, onlyB, changed := Diff(a, b, eq); onlyA != nil || onlyB != nil || changed != nil {
+		t.Errorf("Got %v,%v,%v expected nil,nil,nil", onlyA, onlyB, changed)
+	}
+
+	for i := range 100 {
+		if i%5 != 0 {
+			a.Put(i, fmt.Sprint(i))
+		}
+
+		if i%7 != 0 {
+			value := fmt.Sprint(i)
+			if i%11 == 0 {
+				value = "changed"
+			}
+
+			b.Put(i, value)
+		}
+	}
+
+	b.Put(200, "x")
+
+	var expectedA, expectedB, expectedChanged []int
+
+	for i := range 100 {
+		switch inA, inB := i%5 != 0, i%7 != 0; {
+		case inA && !inB:
+			expectedA = append(expectedA, i)
+		case !inA && inB:
+			expectedB = append(expectedB, i)
+		case inA && inB && i%11 == 0:
+			expectedChanged = append(expectedChanged, i)
+		}
+	}
+
+	expectedB = append(expectedB, 200)
+
+	onlyA, onlyB, changed := Diff(a, b, eq)
+
+	if !slices.Equal(onlyA, expectedA) {
+		t.Errorf("Got onlyA %v expected %v", onlyA, expectedA)
+	}
+
+	if !slices.Equal(onlyB, expectedB) {
+		t.Errorf("Got onlyB %v expected %v", onlyB, expectedB)
+	}
+
+	if !slices.Equal(changed, expectedChanged) {
+		t.Errorf("Got changed %v expected %v", changed, expectedChanged)
+	}
+
+	// Diffing against a reset tree reports everything as only in a.
+	b.Reset()
+
+	if onlyA, onlyB, changed := Diff(a, b, eq); len(onlyA) != a.Len() || onlyB != nil || changed != nil {
+		t.Errorf("Got %v,%v,%v expected %v,nil,nil", len(onlyA), onlyB, changed, a.Len())
+	}
+}
+
+func TestBTreeSubtreeIter(t *testing.T) {
+	tree := New[int, int](3)
+
+	for range tree.SubtreeIter(nil) {
+		t.Errorf("Shouldn't iterate on a nil node")
+	}
+
+	for i := range 50 {
+		tree.Put(i, i*i)
+	}
+
+	var all []int
+	for k := range tree.SubtreeIter(tree.Root()) {
+		all = append(all, k)
+	}
+
+	if !slices.Equal(all, tree.Keys()) {
+		t.Errorf("Got %v expected %v", all, tree.Keys())
+	}
+
+	// Every node's subtree is a contiguous run of the tree's keys.
+	for _, level := range tree.Levels() {
+		for _, n := range level {
+			var keys []int
+
+			for k, v := range tree.SubtreeIter(n) {
+				if v != k*k {
+					t.Errorf("Got %v expected %v for %v", v, k*k, k)
+				}
+
+				keys = append(keys, k)
+			}
+
+			if len(keys) != n.size {
+				t.Errorf("Got %v keys expected %v for node %v", len(keys), n.size, n)
+			}
+
+			for i := 1; i < len(keys); i++ {
+				if keys[i] != keys[i-1]+1 {
+					t.Errorf("Subtree of %v is not contiguous: %v", n, keys)
+
+					break
+				}
+			}
+		}
+	}
+
+	// Early termination.
+	count := 0
+
+	for range tree.SubtreeIter(tree.Root()) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	if count != 3 {
+		t.Errorf("Got %v expected %v", count, 3)
+	}
+}
+
+func TestBTreeAscendDescend(t *testing.T) {
+	for _, order := range []int{3, 4, 7} {
+		tree := New[int, string](order)
+
+		var keys []int
+		for i := 0; i < 100; i += 2 {
+			tree.Put(i, fmt.Sprint(i))
+			keys = append(keys, i)
+		}
+
+		// collect gathers the keys visited by a walk, checking their values.
+		collect := func(walk func(f func(int, string) bool)) []int {
+			var got []int
+
+			walk(func(k int, v string) bool {
+				if v != fmt.Sprint(k) {
+					t.Errorf("Got %v expected %v for %v", v, fmt.Sprint(k), k)
+				}
+
+				got = append(got, k)
+
+				return true
+			})
+
+			return got
+		}
+
+		// want filters keys by pred, optionally in descending order.
+		want := func(pred func(int) bool, desc bool) []int {
+			var res []int
+
+			for _, k := range keys {
+				if pred(k) {
+					res = append(res, k)
+				}
+			}
+
+			if desc {
+				slices.Reverse(res)
+			}
+
+			return res
+		}
+
+		if got, exp := collect(tree.Ascend), keys; !slices.Equal(got, exp) {
+			t.Errorf("order %d Ascend: got %v expected %v", order, got, exp)
+		}
+
+		if got, exp := collect(tree.Descend), want(func(int) bool { return true }, true); !slices.Equal(got, exp) {
+			t.Errorf("order %d Descend: got %v expected %v", order, got, exp)
+		}
+
+		for _, p := range []int{-5, 0, 7, 50, 98, 99, 200} {
+			for _, q := range []int{-1, 0, 13, 60, 98, 150} {
+				got := collect(func(f func(int, string) bool) { tree.AscendRange(p, q, f) })
+				if exp := want(func(k int) bool { return k >= p && k < q }, false); !slices.Equal(got, exp) {
+					t.Errorf("order %d AscendRange(%d, %d): got %v expected %v", order, p, q, got, exp)
+				}
+
+				got = collect(func(f func(int, string) bool) { tree.DescendRange(q, p, f) })
+				if exp := want(func(k int) bool { return k <= q && k > p }, true); !slices.Equal(got, exp) {
+					t.Errorf("order %d DescendRange(%d, %d): got %v expected %v", order, q, p, got, exp)
+				}
+			}
+
+			got := collect(func(f func(int, string) bool) { tree.AscendGreaterOrEqual(p, f) })
+			if exp := want(func(k int) bool { return k >= p }, false); !slices.Equal(got, exp) {
+				t.Errorf("order %d AscendGreaterOrEqual(%d): got %v expected %v", order, p, got, exp)
+			}
+
+			got = collect(func(f func(int, string) bool) { tree.AscendLessThan(p, f) })
+			if exp := want(func(k int) bool { return k < p }, false); !slices.Equal(got, exp) {
+				t.Errorf("order %d AscendLessThan(%d): got %v expected %v", order, p, got, exp)
+			}
+
+			got = collect(func(f func(int, string) bool) { tree.DescendLessOrEqual(p, f) })
+			if exp := want(func(k int) bool { return k <= p }, true); !slices.Equal(got, exp) {
+				t.Errorf("order %d DescendLessOrEqual(%d): got %v expected %v", order, p, got, exp)
+			}
+
+			got = collect(func(f func(int, string) bool) { tree.DescendGreaterThan(p, f) })
+			if exp := want(func(k int) bool { return k > p }, true); !slices.Equal(got, exp) {
+				t.Errorf("order %d DescendGreaterThan(%d): got %v expected %v", order, p, got, exp)
+			}
+		}
+	}
+
+	tree := New[int, int](3)
+	tree.Ascend(func(int, int) bool {
+		t.Errorf("Shouldn't visit an empty tree")
+
+		return true
+	})
+	tree.Descend(func(int, int) bool {
+		t.Errorf("Shouldn't visit an empty tree")
+
+		return true
+	})
+
+	for i := range 20 {
+		tree.Put(i, i)
+	}
+
+	var visited []int
+
+	tree.DescendGreaterThan(5, func(k, _ int) bool {
+		visited = append(visited, k)
+
+		return k > 17
+	})
+
+	if exp := []int{19, 18, 17}; !slices.Equal(visited, exp) {
+		t.Errorf("Got %v expected %v", visited, exp)
+	}
+}
+
+func TestBTreeRebuild(t *testing.T) {
+	tree := New[int, string](3)
+	for i := range 500 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	for _, order := range []int{3, 4, 5, 64} {
+		rebuilt := tree.Rebuild(order)
+
+		if actualValue := rebuilt.MaxChildren(); actualValue != order {
+			t.Errorf("Got %v expected %v", actualValue, order)
+		}
+
+		assertBTreeInvariants(t, rebuilt)
+		assertSubtreeSizes(t, rebuilt.Root())
+
+		if !slices.Equal(rebuilt.Keys(), tree.Keys()) || !slices.Equal(rebuilt.Values(), tree.Values()) {
+			t.Errorf("order %d: rebuilt entries differ from the original", order)
+		}
+
+		// The rebuilt tree is independent of the original.
+		rebuilt.Put(0, "zero")
+		rebuilt.Delete(1)
+
+		if v, _ := tree.Get(0); v != "0" || !tree.Has(1) {
+			t.Errorf("order %d: Rebuild shares state with the original", order)
+		}
+	}
+
+	reversed := NewWith[int, int](4, func(a, b int) int { return b - a })
+	for i := range 10 {
+		reversed.Put(i, i)
+	}
+
+	if actualValue, expectedValue := reversed.Rebuild(16).Keys(), reversed.Keys(); !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue := New[int, int](5).Rebuild(3); !actualValue.IsEmpty() || actualValue.Root() != nil {
+		t.Errorf("Rebuild of an empty tree should be empty")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Rebuild should panic on an order below 3")
+		}
+	}()
+
+	tree.Rebuild(2)
+}
+
+func TestBTreeNodeEntryAt(t *testing.T) {
+	tree := New[int, string](4)
+	for i := range 30 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	for _, level := range tree.Levels() {
+		for _, n := range level {
+			for i, e := range n.Entries() {
+				if k, v := n.EntryAt(i); k != e.Key() || v != e.Value() {
+					t.Errorf("Got %v,%v expected %v,%v", k, v, e.Key(), e.Value())
+				}
+			}
+		}
+	}
+
+	// The node reported by an iterator holds the iterator's current entry.
+	it := tree.Iterator()
+	for it.Next() {
+		n := it.Node()
+
+		found := false
+
+		for i := range n.Entries() {
+			if k, v := n.EntryAt(i); k == it.Key() && v == it.Value() {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("Node %v does not hold the iterator's entry %v", n, it.Key())
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("EntryAt should panic on an out-of-range index")
+		}
+	}()
+
+	tree.Root().EntryAt(len(tree.Root().Entries()))
+}
+
+func TestBTreeSyncTree(t *testing.T) {
+	tree := NewSync[int, int](4)
+
+	var wg sync.WaitGroup
+
+	for i := range 100 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			tree.Put(i, i*10)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			tree.Get(i)
+			tree.Snapshot().Keys()
+		}()
+	}
+
+	wg.Wait()
+
+	if actualValue := tree.Len(); actualValue != 100 {
+		t.Errorf("Got %v expected %v", actualValue, 100)
+	}
+
+	// A snapshot is unaffected by later writes.
+	snapshot := tree.Snapshot()
+
+	tree.WithWrite(func(t *Tree[int, int]) {
+		t.Put(0, -1)
+		t.Put(100, 1000)
+	})
+
+	if v, found := tree.Delete(50); v != 500 || !found {
+		t.Errorf("Got %v,%v expected %v,%v", v, found, 500, true)
+	}
+
+	if _, found := tree.Delete(50); found {
+		t.Errorf("Got %v expected %v", found, false)
+	}
+
+	if v, _ := snapshot.Get(0); v != 0 || snapshot.Len() != 100 || !snapshot.Has(50) || snapshot.Has(100) {
+		t.Errorf("Snapshot should not see later writes")
+	}
+
+	assertBTreeInvariants(t, snapshot)
+
+	if v, _ := tree.Get(0); v != -1 || tree.Len() != 100 || tree.Has(50) || !tree.Has(100) {
+		t.Errorf("Writes should be visible in the current version")
+	}
+
+	// Concurrent increments through WithWrite must not lose updates.
+	for range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			tree.WithWrite(func(t *Tree[int, int]) {
+				v, _ := t.Get(1)
+				t.Put(1, v+1)
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	if actualValue, _ := tree.Get(1); actualValue != 60 {
+		t.Errorf("Got %v expected %v", actualValue, 60)
+	}
+
+	tree.Clear()
+
+	if !tree.IsEmpty() || snapshot.IsEmpty() || tree.Snapshot().MaxChildren() != 4 {
+		t.Errorf("Clear should replace only the current version")
+	}
+}
@@ -0,0 +1,146 @@
+package btree
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/qntx/gods/cmp"
+)
+
+// SyncTree is a concurrency-safe, copy-on-write wrapper around Tree, suited
+// to data that is read often and updated rarely.
+//
+// Reads take no lock: they load the current tree, an immutable snapshot,
+// through an atomic pointer. Writes are serialized by a mutex and applied to
+// a clone of the current tree, which then atomically replaces it. Readers
+// holding an earlier snapshot keep seeing a consistent tree.
+//
+// The price is write amplification: every write copies the whole tree, so a
+// write costs O(n) time and allocations instead of O(log n). Use WithWrite to
+// apply several modifications with a single copy.
+type SyncTree[K comparable, V any] struct {
+	mu   sync.Mutex // Serializes writers.
+	tree atomic.Pointer[Tree[K, V]]
+}
+
+// NewSync creates a new concurrency-safe B-tree of the given order with the
+// built-in comparator. Panics if order is less than 3.
+func NewSync[K cmp.Ordered, V any](order int) *SyncTree[K, V] {
+	return NewSyncWith[K, V](order, cmp.Compare[K])
+}
+
+// NewSyncWith creates a new concurrency-safe B-tree of the given order with a
+// custom comparator. Panics if order is less than 3.
+func NewSyncWith[K comparable, V any](order int, cmp cmp.Comparator[K]) *SyncTree[K, V] {
+	s := &SyncTree[K, V]{}
+	s.tree.Store(NewWith[K, V](order, cmp))
+
+	return s
+}
+
+// Snapshot returns the current version of the tree. It is never modified
+// afterwards, so it can be read freely without locking, but it must not be
+// modified by the caller either.
+// Time complexity: O(1).
+func (s *SyncTree[K, V]) Snapshot() *Tree[K, V] {
+	return s.tree.Load()
+}
+
+// Put inserts or updates a key-value pair.
+// Time complexity: O(n), as the tree is copied.
+func (s *SyncTree[K, V]) Put(key K, value V) {
+	s.WithWrite(func(t *Tree[K, V]) {
+		t.Put(key, value)
+	})
+}
+
+// Delete removes the key-value pair with the given key.
+// Returns the removed value and true if the key was found. The tree is only
+// copied if the key is present.
+// Time complexity: O(n), or O(log n) if the key is absent.
+func (s *SyncTree[K, V]) Delete(key K) (value V, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.tree.Load().Has(key) {
+		return value, false
+	}
+
+	clone := s.clone()
+	value, found = clone.Delete(key)
+	s.tree.Store(clone)
+
+	return value, found
+}
+
+// Clear removes all items, keeping the order, comparator and other settings.
+// Time complexity: O(1).
+func (s *SyncTree[K, V]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tree.Load()
+	cleared := NewWithBias[K, V](t.m, t.cmp, t.bias)
+	cleared.format = t.format
+	s.tree.Store(cleared)
+}
+
+// WithWrite calls f with a private copy of the current tree and then
+// publishes the copy, so several modifications cost a single copy and become
+// visible to readers at once. f must not retain the tree after returning.
+//
+// Example:
+//
+//	st.WithWrite(func(t *Tree[string, int]) {
+//		t.Put("a", 1)
+//		t.Delete("b")
+//	})
+func (s *SyncTree[K, V]) WithWrite(f func(t *Tree[K, V])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clone := s.clone()
+	f(clone)
+	s.tree.Store(clone)
+}
+
+// Get retrieves the value for the given key without locking.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) Get(key K) (V, bool) {
+	return s.tree.Load().Get(key)
+}
+
+// Has checks if the key exists in the tree without locking.
+// Time complexity: O(log n).
+func (s *SyncTree[K, V]) Has(key K) bool {
+	return s.tree.Load().Has(key)
+}
+
+// Keys returns all keys in sorted order without locking.
+// Time complexity: O(n).
+func (s *SyncTree[K, V]) Keys() []K {
+	return s.tree.Load().Keys()
+}
+
+// Values returns all values in sorted key order without locking.
+// Time complexity: O(n).
+func (s *SyncTree[K, V]) Values() []V {
+	return s.tree.Load().Values()
+}
+
+// Len returns the number of items in the tree.
+// Time complexity: O(1).
+func (s *SyncTree[K, V]) Len() int {
+	return s.tree.Load().Len()
+}
+
+// IsEmpty returns true if the tree has no items.
+// Time complexity: O(1).
+func (s *SyncTree[K, V]) IsEmpty() bool {
+	return s.tree.Load().IsEmpty()
+}
+
+// clone returns a deep copy of the current tree. The caller must hold s.mu.
+func (s *SyncTree[K, V]) clone() *Tree[K, V] {
+	return s.tree.Load().Clone().(*Tree[K, V])
+}
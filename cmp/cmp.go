@@ -6,6 +6,7 @@ package cmp
 import (
 	"cmp"
 	"math"
+	"slices"
 	"time"
 )
 
@@ -75,6 +76,23 @@ func Compare[T Ordered](x, y T) int {
 	return 0
 }
 
+// Equal reports whether x equals y, consistently with Compare.
+// Unlike ==, a NaN is considered equal to a NaN; -0.0 is equal to 0.0.
+func Equal[T Ordered](x, y T) bool {
+	return x == y || (IsNaN(x) && IsNaN(y))
+}
+
+// EqualFunc returns an equality function derived from c, reporting whether
+// c(x, y) == 0. Useful for deduplication with slices.CompactFunc or map and
+// set equality helpers.
+//
+// Time complexity: O(1) for creation, one call to c per comparison.
+func EqualFunc[T any](c Comparator[T]) func(x, y T) bool {
+	return func(x, y T) bool {
+		return c(x, y) == 0
+	}
+}
+
 // IsNaN reports whether x is a NaN without requiring the math package.
 // This will always return false if T is not floating-point.
 func IsNaN[T Ordered](x T) bool {
@@ -94,6 +112,283 @@ func Or[T comparable](vals ...T) T {
 	return zero
 }
 
+// FromLess derives a three-way Comparator from a less-than function.
+//
+// Returns:
+//   - -1 if less(x, y)
+//   - +1 if less(y, x)
+//   - 0 otherwise
+//
+// less must be a strict weak ordering (as required by sort.Slice); values that
+// are incomparable under less are treated as equal.
+//
+// Time complexity: O(1) for creation, up to two calls to less per comparison.
+func FromLess[T any](less func(x, y T) bool) Comparator[T] {
+	return func(x, y T) int {
+		if less(x, y) {
+			return -1
+		}
+
+		if less(y, x) {
+			return +1
+		}
+
+		return 0
+	}
+}
+
+// ToLess derives a less-than function from a three-way Comparator, the
+// inverse of FromLess. It reports whether c(x, y) < 0, for use with sort.Slice
+// or other APIs expecting a less function.
+//
+// Time complexity: O(1) for creation, one call to c per comparison.
+func ToLess[T any](c Comparator[T]) func(x, y T) bool {
+	return func(x, y T) bool {
+		return c(x, y) < 0
+	}
+}
+
+// Chain combines comparators into a lexicographic ordering.
+//
+// Each comparator is applied in order and the first non-zero result is
+// returned, so later comparators only break ties left by earlier ones.
+// Returns 0 if all comparators report equality, or if none are given.
+//
+// Time complexity: O(k) per comparison, where k is the number of comparators.
+func Chain[T any](cmps ...Comparator[T]) Comparator[T] {
+	return func(x, y T) int {
+		for _, c := range cmps {
+			if r := c(x, y); r != 0 {
+				return r
+			}
+		}
+
+		return 0
+	}
+}
+
+// Min returns the smaller of a and b according to c.
+//
+// If a and b compare equal, a is returned.
+//
+// Time complexity: O(1), one call to c.
+func Min[T any](c Comparator[T], a, b T) T {
+	if c(b, a) < 0 {
+		return b
+	}
+
+	return a
+}
+
+// Max returns the larger of a and b according to c.
+//
+// If a and b compare equal, a is returned.
+//
+// Time complexity: O(1), one call to c.
+func Max[T any](c Comparator[T], a, b T) T {
+	if c(b, a) > 0 {
+		return b
+	}
+
+	return a
+}
+
+// Clamp restricts v to the range [lo, hi] according to c.
+//
+// Returns:
+//   - lo if v < lo
+//   - hi if v > hi
+//   - v otherwise
+//
+// The result is unspecified if lo > hi under c.
+//
+// Time complexity: O(1), up to two calls to c.
+func Clamp[T any](c Comparator[T], v, lo, hi T) T {
+	if c(v, lo) < 0 {
+		return lo
+	}
+
+	if c(v, hi) > 0 {
+		return hi
+	}
+
+	return v
+}
+
+// IsSorted reports whether s is sorted in ascending order according to c,
+// e.g. the Keys of a tree built with the same comparator.
+//
+// Time complexity: O(n).
+func IsSorted[T any](s []T, c Comparator[T]) bool {
+	return slices.IsSortedFunc(s, c)
+}
+
+// BinarySearch searches for target in s, which must be sorted in ascending
+// order according to c.
+//
+// Returns the position where target is found, or where it would be inserted,
+// and whether it was found. If several elements compare equal to target, the
+// position of the first one is returned.
+//
+// Time complexity: O(log n).
+func BinarySearch[T any](s []T, target T, c Comparator[T]) (int, bool) {
+	return slices.BinarySearchFunc(s, target, c)
+}
+
+// Bool compares two bool values, ordering false before true.
+//
+// Returns:
+//   - -1 if a is false and b is true
+//   - 0 if a == b
+//   - +1 if a is true and b is false
+//
+// Time complexity: O(1).
+func Bool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return +1
+	default:
+		return -1
+	}
+}
+
+// Ptr lifts a comparator over T to one over *T.
+//
+// A nil pointer orders before any non-nil pointer and two nil pointers are
+// equal; non-nil pointers are compared by their pointees using c.
+//
+// Time complexity: O(1) for creation, at most one call to c per comparison.
+func Ptr[T any](c Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		case b == nil:
+			return +1
+		default:
+			return c(*a, *b)
+		}
+	}
+}
+
+// Deref compares two pointers to ordered values by the values they point to,
+// using Compare. It is Ptr(Compare[T]) in function form, so it can be used
+// directly as a Comparator[*T], e.g. for pointer keys in a tree.
+//
+// A nil pointer orders before any non-nil pointer and two nil pointers are
+// equal. Distinct pointers to equal values compare as equal.
+//
+// Time complexity: O(1).
+func Deref[T Ordered](a, b *T) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return +1
+	default:
+		return Compare(*a, *b)
+	}
+}
+
+// NullsLast wraps c so that the sentinel value null orders after every other
+// value, like SQL's NULLS LAST. Two nulls are equal; non-null values are
+// compared with c.
+//
+// Time complexity: O(1) for creation, at most one call to c per comparison.
+func NullsLast[T comparable](c Comparator[T], null T) Comparator[T] {
+	return func(x, y T) int {
+		switch xNull, yNull := x == null, y == null; {
+		case xNull && yNull:
+			return 0
+		case xNull:
+			return +1
+		case yNull:
+			return -1
+		default:
+			return c(x, y)
+		}
+	}
+}
+
+// NullsFirst wraps c so that the sentinel value null orders before every
+// other value, like SQL's NULLS FIRST. Two nulls are equal; non-null values
+// are compared with c.
+//
+// Time complexity: O(1) for creation, at most one call to c per comparison.
+func NullsFirst[T comparable](c Comparator[T], null T) Comparator[T] {
+	return func(x, y T) int {
+		switch xNull, yNull := x == null, y == null; {
+		case xNull && yNull:
+			return 0
+		case xNull:
+			return -1
+		case yNull:
+			return +1
+		default:
+			return c(x, y)
+		}
+	}
+}
+
+// Cached returns a Comparator that orders values by a derived key, computing
+// key(v) at most once per distinct value and comparing the keys with Compare.
+//
+// Useful when deriving the key is expensive (e.g. parsing a version string)
+// and a tree performs many comparisons. The cache grows with every distinct
+// value compared and is never evicted, so memory is O(distinct values). key
+// must be pure, as cached results are reused. The returned Comparator is not
+// safe for concurrent use.
+//
+// Time complexity: O(1) amortized per comparison after the first sight of a value.
+func Cached[T comparable, K Ordered](key func(T) K) Comparator[T] {
+	cache := make(map[T]K)
+
+	lookup := func(v T) K {
+		k, ok := cache[v]
+		if !ok {
+			k = key(v)
+			cache[v] = k
+		}
+
+		return k
+	}
+
+	return func(x, y T) int {
+		return Compare(lookup(x), lookup(y))
+	}
+}
+
+// Pair is a two-component composite value, e.g. a (tenant, id) key. It is
+// comparable, and so usable as a tree key, when A and B are.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// PairComparator returns a Comparator ordering pairs lexicographically: by
+// First using ca, then by Second using cb when the first components are equal.
+//
+// Example:
+//
+//	tree := rbtree.NewWith[cmp.Pair[string, int], V](cmp.PairComparator(cmp.Compare[string], cmp.Compare[int]))
+//
+// Time complexity: O(1) for creation, at most one call to each of ca and cb per comparison.
+func PairComparator[A, B any](ca Comparator[A], cb Comparator[B]) Comparator[Pair[A, B]] {
+	return func(x, y Pair[A, B]) int {
+		if c := ca(x.First, y.First); c != 0 {
+			return c
+		}
+
+		return cb(x.Second, y.Second)
+	}
+}
+
 // TimeComparator compares two time.Time values.
 //
 // Uses time.Time's After and Before methods for precise ordering.
@@ -115,6 +410,18 @@ func TimeComparator(a, b time.Time) int {
 	return 0
 }
 
+// DurationComparator compares two time.Duration values.
+//
+// Returns:
+//   - 1 if a > b
+//   - 0 if a == b
+//   - -1 if a < b
+//
+// Time complexity: O(1).
+func DurationComparator(a, b time.Duration) int {
+	return cmp.Compare(a, b)
+}
+
 // Float64Comparator compares two float64 values directly with an epsilon tolerance.
 //
 // Accounts for floating-point precision by considering values equal if their
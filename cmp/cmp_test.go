@@ -3,10 +3,15 @@ package cmp_test
 import (
 	"cmp"
 	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	godscmp "github.com/qntx/gods/cmp"
+	"github.com/qntx/gods/rbtree"
 )
 
 // TestTimeComparator verifies TimeComparator's behavior with time.Time values.
@@ -164,3 +169,521 @@ func TestCmpFloat64Compare(t *testing.T) {
 		})
 	}
 }
+
+// TestFromLess verifies that FromLess derives a consistent three-way comparator.
+//
+// Checks antisymmetry and agreement with the less function over a small domain,
+// including values that are equivalent under a strict weak ordering.
+func TestFromLess(t *testing.T) {
+	t.Parallel()
+
+	type person struct {
+		name string
+		age  int
+	}
+
+	byAge := godscmp.FromLess(func(a, b person) bool { return a.age < b.age })
+
+	people := []person{{"a", 30}, {"b", 20}, {"c", 30}, {"d", 40}}
+
+	for _, x := range people {
+		for _, y := range people {
+			got := byAge(x, y)
+			if got != -byAge(y, x) {
+				t.Errorf("FromLess(%v, %v) = %d is not antisymmetric", x, y, got)
+			}
+
+			want := cmp.Compare(x.age, y.age)
+			if got != want {
+				t.Errorf("FromLess(%v, %v) = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+
+	ints := godscmp.FromLess(func(a, b int) bool { return a < b })
+	if got := ints(1, 2); got != -1 {
+		t.Errorf("FromLess(1, 2) = %d, want -1", got)
+	}
+
+	if got := ints(2, 1); got != 1 {
+		t.Errorf("FromLess(2, 1) = %d, want 1", got)
+	}
+
+	if got := ints(2, 2); got != 0 {
+		t.Errorf("FromLess(2, 2) = %d, want 0", got)
+	}
+}
+
+// TestChain verifies that Chain orders lexicographically across comparators.
+//
+// Sorts records by three keys and checks that each comparator only breaks ties
+// left by the previous ones, and that a stable sort keeps fully equal records
+// in their original order.
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		dept string
+		age  int
+		name string
+		id   int
+	}
+
+	byDept := func(a, b record) int { return cmp.Compare(a.dept, b.dept) }
+	byAgeDesc := func(a, b record) int { return cmp.Compare(b.age, a.age) }
+	byName := func(a, b record) int { return cmp.Compare(a.name, b.name) }
+
+	records := []record{
+		{"ops", 30, "bob", 0},
+		{"dev", 25, "eve", 1},
+		{"dev", 40, "amy", 2},
+		{"ops", 30, "al", 3},
+		{"dev", 25, "dan", 4},
+		{"ops", 30, "bob", 5},
+	}
+
+	slices.SortStableFunc(records, godscmp.Chain(byDept, byAgeDesc, byName))
+
+	want := []int{2, 4, 1, 3, 0, 5}
+	for i, r := range records {
+		if r.id != want[i] {
+			t.Errorf("Chain order at %d = %d, want %d", i, r.id, want[i])
+		}
+	}
+
+	if got := godscmp.Chain[int]()(1, 2); got != 0 {
+		t.Errorf("Chain() = %d, want 0", got)
+	}
+
+	if got := godscmp.Chain(cmp.Compare[int])(1, 2); got != -1 {
+		t.Errorf("Chain(Compare)(1, 2) = %d, want -1", got)
+	}
+}
+
+// TestMinMaxClamp verifies the comparator-driven Min, Max and Clamp helpers.
+//
+// Uses both natural and reversed orderings to confirm the comparator, not the
+// operators, decides the result, and that ties return the first argument.
+func TestMinMaxClamp(t *testing.T) {
+	t.Parallel()
+
+	asc := cmp.Compare[int]
+	desc := func(a, b int) int { return cmp.Compare(b, a) }
+
+	tests := []struct {
+		name string
+		c    godscmp.Comparator[int]
+		a, b int
+		min  int
+		max  int
+	}{
+		{"asc", asc, 1, 2, 1, 2},
+		{"asc swapped", asc, 2, 1, 1, 2},
+		{"desc", desc, 1, 2, 2, 1},
+		{"equal", asc, 3, 3, 3, 3},
+	}
+
+	for _, tt := range tests {
+		if got := godscmp.Min(tt.c, tt.a, tt.b); got != tt.min {
+			t.Errorf("%s: Min(%d, %d) = %d, want %d", tt.name, tt.a, tt.b, got, tt.min)
+		}
+
+		if got := godscmp.Max(tt.c, tt.a, tt.b); got != tt.max {
+			t.Errorf("%s: Max(%d, %d) = %d, want %d", tt.name, tt.a, tt.b, got, tt.max)
+		}
+	}
+
+	type pair struct{ k, id int }
+
+	byK := func(a, b pair) int { return cmp.Compare(a.k, b.k) }
+	if got := godscmp.Min(byK, pair{1, 0}, pair{1, 1}); got.id != 0 {
+		t.Errorf("Min tie returned id %d, want 0", got.id)
+	}
+
+	if got := godscmp.Max(byK, pair{1, 0}, pair{1, 1}); got.id != 0 {
+		t.Errorf("Max tie returned id %d, want 0", got.id)
+	}
+
+	for _, tt := range []struct {
+		c            godscmp.Comparator[int]
+		v, lo, hi, w int
+	}{
+		{asc, 5, 0, 10, 5},
+		{asc, -1, 0, 10, 0},
+		{asc, 11, 0, 10, 10},
+		{desc, 5, 10, 0, 5},
+		{desc, 11, 10, 0, 10},
+		{desc, -1, 10, 0, 0},
+	} {
+		if got := godscmp.Clamp(tt.c, tt.v, tt.lo, tt.hi); got != tt.w {
+			t.Errorf("Clamp(%d, %d, %d) = %d, want %d", tt.v, tt.lo, tt.hi, got, tt.w)
+		}
+	}
+}
+
+// TestBool verifies that Bool orders false before true.
+func TestBool(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b bool
+		want int
+	}{
+		{false, false, 0},
+		{true, true, 0},
+		{false, true, -1},
+		{true, false, 1},
+	}
+
+	for _, tt := range tests {
+		if got := godscmp.Bool(tt.a, tt.b); got != tt.want {
+			t.Errorf("Bool(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestPtr verifies that Ptr orders nil pointers first and compares pointees.
+//
+// Covers both nil, one nil on either side, and distinct pointers to equal values.
+func TestPtr(t *testing.T) {
+	t.Parallel()
+
+	one, two, otherOne := 1, 2, 1
+	c := godscmp.Ptr(cmp.Compare[int])
+
+	tests := []struct {
+		name string
+		a, b *int
+		want int
+	}{
+		{"both nil", nil, nil, 0},
+		{"nil first", nil, &one, -1},
+		{"nil second", &one, nil, 1},
+		{"less", &one, &two, -1},
+		{"greater", &two, &one, 1},
+		{"equal pointees", &one, &otherOne, 0},
+	}
+
+	for _, tt := range tests {
+		if got := c(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: Ptr = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	type task struct {
+		done     bool
+		priority *int
+	}
+
+	tasks := []task{{true, &one}, {false, nil}, {false, &two}, {false, &one}}
+	slices.SortStableFunc(tasks, godscmp.Chain(
+		func(a, b task) int { return godscmp.Bool(a.done, b.done) },
+		func(a, b task) int { return c(a.priority, b.priority) },
+	))
+
+	if tasks[0].priority != nil || *tasks[1].priority != 1 || *tasks[2].priority != 2 || !tasks[3].done {
+		t.Errorf("Unexpected order %v", tasks)
+	}
+}
+
+// TestDeref verifies that Deref orders pointers by their pointees, with nil first.
+func TestDeref(t *testing.T) {
+	t.Parallel()
+
+	one, two, otherOne := 1, 2, 1
+
+	tests := []struct {
+		name string
+		a, b *int
+		want int
+	}{
+		{"both nil", nil, nil, 0},
+		{"nil first", nil, &two, -1},
+		{"nil second", &two, nil, 1},
+		{"less", &one, &two, -1},
+		{"greater", &two, &one, 1},
+		{"same pointer", &one, &one, 0},
+		{"equal pointees", &one, &otherOne, 0},
+	}
+
+	for _, tt := range tests {
+		if got := godscmp.Deref(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: Deref = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	var c godscmp.Comparator[*string] = godscmp.Deref[string]
+
+	b, a := "b", "a"
+	keys := []*string{&b, nil, &a}
+	slices.SortFunc(keys, c)
+
+	if keys[0] != nil || *keys[1] != "a" || *keys[2] != "b" {
+		t.Errorf("Unexpected order %v", keys)
+	}
+}
+
+// TestNulls verifies NullsLast and NullsFirst ordering of a sentinel value.
+//
+// Covers both values null, one null on either side, and neither null, and
+// checks that non-null values still follow the wrapped comparator.
+func TestNulls(t *testing.T) {
+	t.Parallel()
+
+	const null = -1
+
+	last := godscmp.NullsLast(cmp.Compare[int], null)
+	first := godscmp.NullsFirst(cmp.Compare[int], null)
+
+	tests := []struct {
+		name        string
+		x, y        int
+		last, first int
+	}{
+		{"both null", null, null, 0, 0},
+		{"x null", null, 5, 1, -1},
+		{"y null", 5, null, -1, 1},
+		{"neither less", 1, 5, -1, -1},
+		{"neither greater", 5, 1, 1, 1},
+		{"neither equal", 3, 3, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := last(tt.x, tt.y); got != tt.last {
+			t.Errorf("%s: NullsLast(%d, %d) = %d, want %d", tt.name, tt.x, tt.y, got, tt.last)
+		}
+
+		if got := first(tt.x, tt.y); got != tt.first {
+			t.Errorf("%s: NullsFirst(%d, %d) = %d, want %d", tt.name, tt.x, tt.y, got, tt.first)
+		}
+	}
+
+	values := []int{3, null, 1, null, 2}
+	slices.SortFunc(values, last)
+
+	if want := []int{1, 2, 3, null, null}; !slices.Equal(values, want) {
+		t.Errorf("NullsLast sort = %v, want %v", values, want)
+	}
+
+	slices.SortFunc(values, first)
+
+	if want := []int{null, null, 1, 2, 3}; !slices.Equal(values, want) {
+		t.Errorf("NullsFirst sort = %v, want %v", values, want)
+	}
+}
+
+// TestCached verifies that Cached orders by the derived key and computes it
+// once per distinct value.
+func TestCached(t *testing.T) {
+	t.Parallel()
+
+	calls := map[string]int{}
+	parse := func(s string) int {
+		calls[s]++
+
+		n := 0
+		for _, r := range s {
+			n = n*10 + int(r-'0')
+		}
+
+		return n
+	}
+
+	c := godscmp.Cached(parse)
+
+	values := []string{"10", "9", "100", "1", "9", "10"}
+	slices.SortStableFunc(values, c)
+
+	if want := []string{"1", "9", "9", "10", "10", "100"}; !slices.Equal(values, want) {
+		t.Errorf("Cached sort = %v, want %v", values, want)
+	}
+
+	for s, n := range calls {
+		if n != 1 {
+			t.Errorf("key(%q) called %d times, want 1", s, n)
+		}
+	}
+
+	if got := c("007", "7"); got != 0 {
+		t.Errorf("Cached(%q, %q) = %d, want 0", "007", "7", got)
+	}
+}
+
+// TestDurationComparator verifies DurationComparator's ordering of time.Duration values.
+func TestDurationComparator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b time.Duration
+		want int
+	}{
+		{"equal", time.Second, 1000 * time.Millisecond, 0},
+		{"less", -time.Minute, time.Nanosecond, -1},
+		{"greater", time.Hour, time.Minute, 1},
+	}
+
+	for _, tt := range tests {
+		if got := godscmp.DurationComparator(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: DurationComparator(%v, %v) = %d, want %d", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestPairComparator verifies that PairComparator orders by First and breaks
+// ties on Second.
+func TestPairComparator(t *testing.T) {
+	t.Parallel()
+
+	type P = godscmp.Pair[string, int]
+
+	c := godscmp.PairComparator(cmp.Compare[string], cmp.Compare[int])
+
+	tests := []struct {
+		name string
+		a, b P
+		want int
+	}{
+		{"equal", P{"a", 1}, P{"a", 1}, 0},
+		{"first decides", P{"a", 9}, P{"b", 1}, -1},
+		{"tie on first, second less", P{"a", 1}, P{"a", 2}, -1},
+		{"tie on first, second greater", P{"a", 3}, P{"a", 2}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := c(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: PairComparator(%v, %v) = %d, want %d", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	pairs := []P{{"b", 1}, {"a", 2}, {"b", 0}, {"a", 1}}
+	slices.SortFunc(pairs, c)
+
+	if want := []P{{"a", 1}, {"a", 2}, {"b", 0}, {"b", 1}}; !slices.Equal(pairs, want) {
+		t.Errorf("PairComparator sort = %v, want %v", pairs, want)
+	}
+}
+
+// TestEqual verifies that Equal and EqualFunc agree with Compare, including
+// NaN equality.
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	nan, negZero := math.NaN(), math.Copysign(0, -1)
+
+	tests := []struct {
+		name string
+		x, y float64
+		want bool
+	}{
+		{"equal", 1.5, 1.5, true},
+		{"different", 1.5, 2.5, false},
+		{"both NaN", nan, nan, true},
+		{"NaN and number", nan, 0, false},
+		{"signed zeros", negZero, 0, true},
+	}
+
+	eq := godscmp.EqualFunc(godscmp.Compare[float64])
+
+	for _, tt := range tests {
+		if got := godscmp.Equal(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: Equal(%v, %v) = %v, want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+
+		if got := eq(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: EqualFunc(Compare)(%v, %v) = %v, want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+
+		if got := godscmp.Compare(tt.x, tt.y) == 0; got != tt.want {
+			t.Errorf("%s: Compare(%v, %v) == 0 is %v, want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	words := []string{"a", "A", "b", "B", "b"}
+	folded := slices.CompactFunc(words, godscmp.EqualFunc(func(x, y string) int {
+		return cmp.Compare(strings.ToLower(x), strings.ToLower(y))
+	}))
+
+	if want := []string{"a", "b"}; !slices.Equal(folded, want) {
+		t.Errorf("CompactFunc(EqualFunc) = %v, want %v", folded, want)
+	}
+}
+
+// TestIsSortedAndBinarySearch verifies IsSorted and BinarySearch under a
+// custom descending comparator.
+func TestIsSortedAndBinarySearch(t *testing.T) {
+	t.Parallel()
+
+	desc := func(x, y int) int { return cmp.Compare(y, x) }
+	s := []int{9, 7, 7, 4, 1}
+
+	if !godscmp.IsSorted(s, desc) {
+		t.Errorf("IsSorted(%v, desc) = false, want true", s)
+	}
+
+	if godscmp.IsSorted(s, godscmp.Compare[int]) {
+		t.Errorf("IsSorted(%v, Compare) = true, want false", s)
+	}
+
+	if !godscmp.IsSorted([]int{}, desc) {
+		t.Errorf("IsSorted of an empty slice = false, want true")
+	}
+
+	tests := []struct {
+		target int
+		index  int
+		found  bool
+	}{
+		{10, 0, false},
+		{9, 0, true},
+		{7, 1, true},
+		{5, 3, false},
+		{1, 4, true},
+		{0, 5, false},
+	}
+
+	for _, tt := range tests {
+		if index, found := godscmp.BinarySearch(s, tt.target, desc); index != tt.index || found != tt.found {
+			t.Errorf("BinarySearch(%v, %d) = (%d, %v), want (%d, %v)", s, tt.target, index, found, tt.index, tt.found)
+		}
+	}
+}
+
+// TestToLess verifies that sorting with ToLess matches the order of a tree
+// built with the same comparator.
+func TestToLess(t *testing.T) {
+	t.Parallel()
+
+	byLen := godscmp.Chain(
+		func(x, y string) int { return cmp.Compare(len(x), len(y)) },
+		godscmp.Compare[string],
+	)
+
+	tree := rbtree.NewWith[string, struct{}](byLen)
+	words := []string{"kiwi", "fig", "banana", "apple", "date", "cherry", "plum", "pear"}
+
+	for _, w := range words {
+		tree.Put(w, struct{}{})
+	}
+
+	rand.New(rand.NewSource(1)).Shuffle(len(words), func(i, j int) { words[i], words[j] = words[j], words[i] })
+
+	less := godscmp.ToLess(byLen)
+	sort.Slice(words, func(i, j int) bool { return less(words[i], words[j]) })
+
+	if want := tree.Keys(); !slices.Equal(words, want) {
+		t.Errorf("sort.Slice(ToLess) = %v, want %v", words, want)
+	}
+
+	if less("fig", "fig") || !less("fig", "kiwi") || less("kiwi", "fig") {
+		t.Errorf("ToLess should report strict less-than")
+	}
+
+	// Round-tripping through FromLess preserves the comparator's order.
+	roundTrip := godscmp.FromLess(less)
+	for _, pair := range [][2]string{{"fig", "kiwi"}, {"pear", "kiwi"}, {"date", "date"}} {
+		if got, want := roundTrip(pair[0], pair[1]), byLen(pair[0], pair[1]); got != want {
+			t.Errorf("FromLess(ToLess(c))(%q, %q) = %d, want %d", pair[0], pair[1], got, want)
+		}
+	}
+}
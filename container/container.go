@@ -6,6 +6,7 @@
 //
 // Key features:
 //   - Container: Base interface for all data structures.
+//   - Cloneable: Data structures that can copy themselves.
 //   - Iterators: Stateful iteration (defined separately).
 //   - Enumerable: Ruby-inspired container functions (defined separately).
 //   - Serialization: JSON marshalers and unmarshalers (defined separately).
@@ -49,6 +50,30 @@ type Container[T any] interface {
 	ToSlice() []T
 }
 
+// Cloneable is implemented by data structures that can produce an independent
+// copy of themselves.
+//
+// By convention, types behind one of this package's interfaces return that
+// interface from Clone (e.g. Map[K, V] or Set[T]), since Go does not allow a
+// second Clone method returning the concrete type; they are Cloneable[Map[K, V]]
+// or Cloneable[Set[T]]. Other types, such as priority queues, return their
+// concrete pointer type and are Cloneable of themselves, so generic code can
+// constrain on [T Cloneable[T]].
+//
+// Example usage:
+//
+//	func Snapshot[T container.Cloneable[T]](items []T) []T {
+//	    out := make([]T, len(items))
+//	    for i, it := range items {
+//	        out[i] = it.Clone()
+//	    }
+//	    return out
+//	}
+type Cloneable[T any] interface {
+	// Clone returns a copy that shares no mutable state with the receiver.
+	Clone() T
+}
+
 // GetSortedValues returns a sorted slice of the container's elements for ordered types.
 //
 // It uses the natural ordering of type T, as defined by the cmp.Ordered constraint.
@@ -0,0 +1,64 @@
+package container
+
+// IteratorWithKey is a stateful, forward-only iterator over key-value pairs.
+//
+// It complements the iter.Seq2 functions (Iter) offered by ordered maps for
+// callers that need to pause, resume or reposition a traversal. An iterator
+// starts positioned before the first element; Key and Value are only valid
+// after Next or First has returned true.
+//
+// Example usage:
+//
+//	it := m.Iterator()
+//	for it.Next() {
+//	    fmt.Println(it.Key(), it.Value())
+//	}
+type IteratorWithKey[K comparable, V any] interface {
+	// Next moves the iterator to the next element and returns true if there was
+	// one. After it returns false, the iterator is positioned past the end.
+	Next() bool
+
+	// Key returns the key of the current element.
+	Key() K
+
+	// Value returns the value of the current element.
+	Value() V
+
+	// Begin resets the iterator to its initial state (before the first element).
+	// Call Next to fetch the first element, if any.
+	Begin()
+
+	// First moves the iterator to the first element and returns true if there
+	// was one.
+	First() bool
+}
+
+// ReverseIteratorWithKey is a stateful, bidirectional iterator over key-value
+// pairs.
+//
+// It extends IteratorWithKey with backward movement, mirroring the RIter
+// functions offered by ordered maps. An iterator positioned with End starts
+// past the last element, so repeated calls to Prev walk the map in reverse.
+//
+// Example usage:
+//
+//	it := m.ReverseIterator()
+//	for it.Prev() {
+//	    fmt.Println(it.Key(), it.Value())
+//	}
+type ReverseIteratorWithKey[K comparable, V any] interface {
+	IteratorWithKey[K, V]
+
+	// Prev moves the iterator to the previous element and returns true if there
+	// was one. After it returns false, the iterator is positioned before the
+	// beginning.
+	Prev() bool
+
+	// End moves the iterator past the last element.
+	// Call Prev to fetch the last element, if any.
+	End()
+
+	// Last moves the iterator to the last element and returns true if there
+	// was one.
+	Last() bool
+}
@@ -58,7 +58,7 @@ type Map[K comparable, V any] interface {
 	Entries() (keys []K, vals []V)
 
 	// Clone returns a clone of the map using the same implementation,
-	// duplicating all keys and values.
+	// duplicating all keys and values. Every Map is a Cloneable[Map[K, V]].
 	Clone() Map[K, V]
 }
 
@@ -180,3 +180,32 @@ type OrderedBiMap[K comparable, V comparable] interface {
 	// Returns the key and true if the value was found and removed, false if the value was not present.
 	DeleteValue(val V) (key K, found bool)
 }
+
+// MapEqual reports whether two maps contain the same key-value pairs, using ==
+// to compare values. The maps may have different implementations; ordering is
+// ignored.
+//
+// Time complexity: O(n) lookups in b, where n is the size of a.
+func MapEqual[K, V comparable](a, b Map[K, V]) bool {
+	return MapEqualFunc(a, b, func(x, y V) bool { return x == y })
+}
+
+// MapEqualFunc is like MapEqual, but compares values using eq. Keys are still
+// compared by the maps' own lookup semantics.
+//
+// Time complexity: O(n) lookups in b, where n is the size of a.
+func MapEqualFunc[K comparable, V1, V2 any](a Map[K, V1], b Map[K, V2], eq func(V1, V2) bool) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	keys, vals := a.Entries()
+	for i, k := range keys {
+		v, found := b.Get(k)
+		if !found || !eq(vals[i], v) {
+			return false
+		}
+	}
+
+	return true
+}
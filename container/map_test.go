@@ -0,0 +1,82 @@
+package container_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/qntx/gods/container"
+	"github.com/qntx/gods/hashmap"
+	"github.com/qntx/gods/rbtree"
+)
+
+func TestMapEqual(t *testing.T) {
+	a := hashmap.New[int, string]()
+	b := rbtree.New[int, string]()
+
+	if !container.MapEqual[int, string](a, b) {
+		t.Errorf("Empty maps should be equal")
+	}
+
+	for i := range 5 {
+		a.Put(i, fmt.Sprint(i))
+		b.Put(4-i, fmt.Sprint(4-i))
+	}
+
+	if !container.MapEqual[int, string](a, b) || !container.MapEqual[int, string](b, a) {
+		t.Errorf("Maps with the same pairs should be equal regardless of implementation")
+	}
+
+	b.Put(2, "x")
+
+	if container.MapEqual[int, string](a, b) {
+		t.Errorf("Maps with different values should not be equal")
+	}
+
+	caseless := func(x, y string) bool { return strings.EqualFold(x, y) }
+
+	b.Put(2, "2")
+	a.Put(5, "five")
+	b.Put(6, "FIVE")
+
+	if container.MapEqualFunc[int, string, string](a, b, caseless) {
+		t.Errorf("Maps with different keys should not be equal")
+	}
+
+	b.Delete(6)
+	b.Put(5, "FIVE")
+
+	if !container.MapEqualFunc[int, string, string](a, b, caseless) {
+		t.Errorf("Maps should be equal under the custom value comparison")
+	}
+}
+
+func cloneAll[T container.Cloneable[T]](items []T) []T {
+	out := make([]T, len(items))
+	for i, item := range items {
+		out[i] = item.Clone()
+	}
+
+	return out
+}
+
+func TestCloneable(t *testing.T) {
+	maps := []container.Map[int, string]{hashmap.New[int, string](), rbtree.New[int, string]()}
+	for _, m := range maps {
+		m.Put(1, "a")
+	}
+
+	clones := cloneAll(maps)
+
+	for i, c := range clones {
+		c.Put(2, "b")
+
+		if maps[i].Len() != 1 || c.Len() != 2 {
+			t.Errorf("Clone %T should not share state with the original", c)
+		}
+
+		if _, ok := c.(*rbtree.Tree[int, string]); ok != (i == 1) {
+			t.Errorf("Clone %T should keep the implementation of %T", c, maps[i])
+		}
+	}
+}
@@ -7,6 +7,9 @@ import "github.com/qntx/gods/cmp"
 // Implementations (e.g., array-based or linked-list queues) must provide all
 // operations defined here, including those inherited from Container[T] (e.g., Len, IsEmpty, Clear).
 // Type parameter T must be comparable to enable equality checks for elements.
+//
+// Double-ended queues can satisfy Queue by mapping Enqueue to PushBack,
+// Dequeue to PopFront and Peek to Front.
 type Queue[T comparable] interface {
 	Container[T]
 
@@ -25,13 +28,19 @@ type Queue[T comparable] interface {
 }
 
 // PQueue is a generic interface for a priority queue.
-// It supports adding elements to the back and removing them from the front.
-// Implementations (e.g., array-based or linked-list priority queues) must provide all
+// It supports adding elements with a priority and removing them in priority order.
+// Implementations (e.g., binary or pairing heaps) must provide all
 // operations defined here, including those inherited from Container[T] (e.g., Len, IsEmpty, Clear).
 // Type parameter T must be comparable to enable equality checks for elements.
 type PQueue[T comparable, V cmp.Ordered] interface {
 	Container[T]
 
+	// Len returns the number of elements in the queue.
+	Len() int
+
+	// Contains returns true if the value is present in the queue.
+	Contains(val T) bool
+
 	// Enqueue adds an element to the queue.
 	Enqueue(val T, priority V)
 
@@ -40,9 +49,9 @@ type PQueue[T comparable, V cmp.Ordered] interface {
 	// or the zero value of T and false if the queue is empty.
 	Dequeue() (val T, priority V, ok bool)
 
-	// Peek returns the front element of the queue without removing it.
-	// Returns the element and true if the queue is non-empty,
-	// or the zero value of T and false if the queue is empty.
+	// Peek returns the front element of the queue and its priority without removing it.
+	// Returns the element, its priority and true if the queue is non-empty,
+	// or zero values and false if the queue is empty.
 	Peek() (val T, priority V, ok bool)
 }
 
@@ -9,7 +9,7 @@ type Set[T comparable] interface {
 	Container[T]
 
 	// Clone returns a clone of the set using the same
-	// implementation, duplicating all keys.
+	// implementation, duplicating all keys. Every Set is a Cloneable[Set[T]].
 	Clone() Set[T]
 
 	// Add adds an element to the set. Returns whether
@@ -133,3 +133,60 @@ type Set[T comparable] interface {
 	// range over.
 	Iter() iter.Seq[T]
 }
+
+// Union returns a new set, created by newSet, holding every element of a and
+// b. Unlike the Union method it returns the concrete type S, and a and b may
+// have different implementations.
+//
+// Elements are added in a's iteration order, then b's, so an insertion-ordered
+// result such as a linkedhashset lists a's elements first, followed by those
+// only in b:
+//
+//	u := container.Union(linkedhashset.New[int], a, b) // *linkedhashset.Set[int]
+//
+// Time complexity: O(n + m) insertions, for sets of n and m elements.
+func Union[T comparable, S Set[T]](newSet func() S, a, b Set[T]) S {
+	result := newSet()
+
+	for item := range a.Iter() {
+		result.Add(item)
+	}
+
+	for item := range b.Iter() {
+		result.Add(item)
+	}
+
+	return result
+}
+
+// Intersect returns a new set, created by newSet, holding the elements of a
+// that are also in b, added in a's iteration order.
+//
+// Time complexity: O(n) lookups in b, where n is the size of a.
+func Intersect[T comparable, S Set[T]](newSet func() S, a, b Set[T]) S {
+	result := newSet()
+
+	for item := range a.Iter() {
+		if b.ContainsOne(item) {
+			result.Add(item)
+		}
+	}
+
+	return result
+}
+
+// Difference returns a new set, created by newSet, holding the elements of a
+// that are not in b, added in a's iteration order.
+//
+// Time complexity: O(n) lookups in b, where n is the size of a.
+func Difference[T comparable, S Set[T]](newSet func() S, a, b Set[T]) S {
+	result := newSet()
+
+	for item := range a.Iter() {
+		if !b.ContainsOne(item) {
+			result.Add(item)
+		}
+	}
+
+	return result
+}
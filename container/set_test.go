@@ -0,0 +1,41 @@
+package container_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/qntx/gods/container"
+	"github.com/qntx/gods/hashset"
+	"github.com/qntx/gods/linkedhashset"
+)
+
+func TestSetOperations(t *testing.T) {
+	a := linkedhashset.NewFrom(5, 1, 4, 2)
+	b := hashset.New(4, 9, 5, 7)
+
+	union := container.Union(linkedhashset.New[int], a, linkedhashset.NewFrom(7, 4, 9))
+	if actualValue, expectedValue := union.Values(), []int{5, 1, 4, 2, 7, 9}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	intersection := container.Intersect(linkedhashset.New[int], a, b)
+	if actualValue, expectedValue := intersection.Values(), []int{5, 4}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	difference := container.Difference(linkedhashset.New[int], a, b)
+	if actualValue, expectedValue := difference.Values(), []int{1, 2}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// The operands are left unchanged, and the result type may differ from theirs.
+	newHashSet := func() *hashset.Set[int] { return hashset.New[int]() }
+
+	if actualValue := container.Union(newHashSet, b, a); actualValue.Len() != 6 || a.Len() != 4 || b.Len() != 4 {
+		t.Errorf("Got lengths %v,%v,%v expected %v,%v,%v", actualValue.Len(), a.Len(), b.Len(), 6, 4, 4)
+	}
+
+	if actualValue := container.Intersect(linkedhashset.New[int], a, linkedhashset.New[int]()); !actualValue.IsEmpty() {
+		t.Errorf("Got %v expected an empty set", actualValue.Values())
+	}
+}
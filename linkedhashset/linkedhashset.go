@@ -2,7 +2,8 @@
 //
 // It is backed by a hash table to store values and doubly-linked list to store ordering.
 //
-// Note that insertion-order is not affected if an element is re-inserted into the set.
+// Note that insertion-order is not affected if an element is re-inserted into the set,
+// except in bounded sets created with NewBounded, which behave as LRU sets.
 //
 // Structure is not thread safe.
 //
@@ -29,6 +30,7 @@ var _ json.Unmarshaler = (*Set[int])(nil)
 type Set[T comparable] struct {
 	table    map[T]*list.Element
 	ordering *list.List
+	maxSize  int // Maximum number of elements, or 0 if unbounded.
 }
 
 // New instantiates a new empty set and adds the passed values, if any, to the set.
@@ -53,32 +55,73 @@ func NewWith[T comparable](size int, values ...T) *Set[T] {
 	return set
 }
 
-// Add adds the items (one or more) to the set.
-// Note that insertion-order is not affected if an element is re-inserted into the set.
+// NewBounded instantiates a new empty set holding at most maxSize elements,
+// evicting in least-recently-added order (an LRU set).
+//
+// When the set is full, adding a new element first evicts the front (oldest)
+// element. Re-adding an element already in the set moves it to the back
+// (most recent). Panics if maxSize is less than 1.
+func NewBounded[T comparable](maxSize int) *Set[T] {
+	if maxSize < 1 {
+		panic("linkedhashset: maxSize must be positive")
+	}
+
+	set := NewWith[T](min(maxSize, defaultSize))
+	set.maxSize = maxSize
+
+	return set
+}
+
+// Add adds the item to the set, returning true if it was not already present.
+// Note that insertion-order is not affected if an element is re-inserted into the set,
+// unless the set is bounded, in which case the element is moved to the back.
 func (set *Set[T]) Add(item T) bool {
-	if _, contains := set.table[item]; !contains {
-		element := set.ordering.PushBack(item)
-		set.table[item] = element
+	_, contains := set.table[item]
+	set.AddEvict(item)
+
+	return !contains
+}
 
-		return true
+// AddEvict adds the item to the set like Add, and returns the element evicted
+// to make room for it, if any. Only bounded sets evict.
+func (set *Set[T]) AddEvict(item T) (evicted T, ok bool) {
+	if element, contains := set.table[item]; contains {
+		if set.maxSize > 0 {
+			set.ordering.MoveToBack(element)
+		}
+
+		return evicted, false
 	}
 
-	return false
+	if set.maxSize > 0 && set.Len() >= set.maxSize {
+		evicted, ok = set.Pop()
+	}
+
+	set.table[item] = set.ordering.PushBack(item)
+
+	return evicted, ok
+}
+
+// MaxSize returns the maximum number of elements of a bounded set, or 0 if
+// the set is unbounded.
+func (set *Set[T]) MaxSize() int {
+	return set.maxSize
 }
 
-// Append adds the items (one or more) to the set.
-// Note that insertion-order is not affected if an element is re-inserted into the set.
+// Append adds the items (one or more) to the set, returning how many were not
+// already present.
+// Note that insertion-order is not affected if an element is re-inserted into the set,
+// unless the set is bounded, in which case the element is moved to the back.
 func (set *Set[T]) Append(items ...T) int {
-	prevlen := set.Len()
+	added := 0
 
 	for _, item := range items {
-		if _, contains := set.table[item]; !contains {
-			element := set.ordering.PushBack(item)
-			set.table[item] = element
+		if set.Add(item) {
+			added++
 		}
 	}
 
-	return set.Len() - prevlen
+	return added
 }
 
 // Remove removes the item from the set.
@@ -101,6 +144,22 @@ func (set *Set[T]) RemoveAll(items ...T) {
 	}
 }
 
+// RetainAll removes from the set every element not contained in other,
+// keeping the remaining elements in their insertion order.
+// Unlike Intersect it modifies the set in place, in O(n) membership tests.
+func (set *Set[T]) RetainAll(other container.Set[T]) {
+	for element := set.ordering.Front(); element != nil; {
+		next := element.Next()
+
+		if item := element.Value.(T); !other.ContainsOne(item) {
+			set.ordering.Remove(element)
+			delete(set.table, item)
+		}
+
+		element = next
+	}
+}
+
 // Pop removes and returns an arbitrary item from the set.
 func (set *Set[T]) Pop() (v T, ok bool) {
 	if element := set.ordering.Front(); element != nil {
@@ -112,6 +171,64 @@ func (set *Set[T]) Pop() (v T, ok bool) {
 	return
 }
 
+// PopBack removes and returns the newest item (the most recently used one in
+// a bounded set), the counterpart of Pop, which removes the oldest.
+func (set *Set[T]) PopBack() (v T, ok bool) {
+	if element := set.ordering.Back(); element != nil {
+		set.Remove(element.Value.(T))
+
+		return element.Value.(T), true
+	}
+
+	return
+}
+
+// At returns the element at the given insertion position, where 0 is the oldest element.
+// Returns the zero value and false if index is out of range.
+// The list is walked from the nearer end, so the cost is O(n).
+func (set *Set[T]) At(index int) (v T, ok bool) {
+	n := set.Len()
+	if index < 0 || index >= n {
+		return v, false
+	}
+
+	if index < n/2 {
+		e := set.ordering.Front()
+		for range index {
+			e = e.Next()
+		}
+
+		return e.Value.(T), true
+	}
+
+	e := set.ordering.Back()
+	for range n - 1 - index {
+		e = e.Prev()
+	}
+
+	return e.Value.(T), true
+}
+
+// IndexOf returns the insertion position of item, where 0 is the oldest element.
+// Returns -1 and false if the item is not in the set.
+// Membership is checked in O(1), but locating the position costs O(n).
+func (set *Set[T]) IndexOf(item T) (int, bool) {
+	if _, contains := set.table[item]; !contains {
+		return -1, false
+	}
+
+	i := 0
+	for e := set.ordering.Front(); e != nil; e = e.Next() {
+		if e.Value.(T) == item {
+			return i, true
+		}
+
+		i++
+	}
+
+	return -1, false
+}
+
 // ContainsOne check if item is present in the set.
 func (set *Set[T]) ContainsOne(item T) bool {
 	_, contains := set.table[item]
@@ -279,6 +396,33 @@ func (set *Set[T]) SymmetricDifference(another container.Set[T]) container.Set[T
 	return result
 }
 
+// Filter returns a new set with the elements for which pred returns true,
+// in their original insertion order.
+func (set *Set[T]) Filter(pred func(T) bool) *Set[T] {
+	result := New[T]()
+
+	for item := range set.Iter() {
+		if pred(item) {
+			result.Add(item)
+		}
+	}
+
+	return result
+}
+
+// Map returns a new set with f applied to every element of set, in insertion order.
+// If several elements map to the same value, it keeps the position of the first.
+// It is a function rather than a method because methods cannot declare type parameters.
+func Map[T, U comparable](set *Set[T], f func(T) U) *Set[U] {
+	result := NewWith[U](set.Len())
+
+	for item := range set.Iter() {
+		result.Add(f(item))
+	}
+
+	return result
+}
+
 // IsEmpty returns true if set does not contain any elements.
 func (set *Set[T]) IsEmpty() bool {
 	return set.Len() == 0
@@ -324,22 +468,35 @@ func (set *Set[T]) Iter() iter.Seq[T] {
 // Clone returns a clone of the set using the same
 // implementation, duplicating all keys.
 func (set *Set[T]) Clone() container.Set[T] {
-	return NewFrom(set.Values()...)
+	clone := NewFrom(set.Values()...)
+	clone.maxSize = set.maxSize
+
+	return clone
 }
 
-// MarshalJSON outputs the JSON representation of the set.
+// MarshalJSON outputs the JSON representation of the set, an array of its
+// elements in insertion order.
 func (set *Set[T]) MarshalJSON() ([]byte, error) {
 	return json.Marshal(set.Values())
 }
 
-// UnmarshalJSON populates the set from the input JSON representation.
+// UnmarshalJSON populates the set from the input JSON representation,
+// replacing its contents. Elements keep the order of the input array, so a
+// MarshalJSON round trip preserves insertion order. Duplicate elements are
+// ignored and keep the position of their first occurrence, also in bounded
+// sets, which otherwise move re-added elements to the back.
 func (set *Set[T]) UnmarshalJSON(data []byte) error {
 	var elements []T
 
 	err := json.Unmarshal(data, &elements)
 	if err == nil {
 		set.Clear()
-		set.Append(elements...)
+
+		for _, element := range elements {
+			if !set.ContainsOne(element) {
+				set.AddEvict(element)
+			}
+		}
 	}
 
 	return err
@@ -2,6 +2,8 @@ package linkedhashset_test
 
 import (
 	"encoding/json"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -136,6 +138,49 @@ func TestSetSerialization(t *testing.T) {
 	assert()
 }
 
+func TestSetSerializationOrder(t *testing.T) {
+	set := linkedhashset.New[string]()
+	set.Append("m", "z", "a", "k", "b")
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if actualValue, expectedValue := string(data), `["m","z","a","k","b"]`; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	decoded := linkedhashset.New[string]()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if actualValue, expectedValue := decoded.Values(), set.Values(); !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// Duplicates keep the position of their first occurrence.
+	input := []byte(`["b","a","b","c","a"]`)
+
+	if err := json.Unmarshal(input, decoded); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if actualValue, expectedValue := decoded.Values(), []string{"b", "a", "c"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	bounded := linkedhashset.NewBounded[string](3)
+	if err := json.Unmarshal(input, bounded); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if actualValue, expectedValue := bounded.Values(), []string{"b", "a", "c"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
 func TestSetString(t *testing.T) {
 	c := linkedhashset.New[int]()
 	c.Append(1)
@@ -213,3 +258,185 @@ func TestSetDifference(t *testing.T) {
 		t.Errorf("Got %v expected %v", actualValue, true)
 	}
 }
+
+func TestSetAtAndIndexOf(t *testing.T) {
+	set := linkedhashset.New[string]()
+
+	if _, ok := set.At(0); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+
+	set.Append("c", "a", "d", "b", "e")
+
+	for i, expectedValue := range []string{"c", "a", "d", "b", "e"} {
+		if actualValue, ok := set.At(i); actualValue != expectedValue || !ok {
+			t.Errorf("Got %v,%v expected %v,%v", actualValue, ok, expectedValue, true)
+		}
+
+		if actualValue, ok := set.IndexOf(expectedValue); actualValue != i || !ok {
+			t.Errorf("Got %v,%v expected %v,%v", actualValue, ok, i, true)
+		}
+	}
+
+	if _, ok := set.At(-1); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+
+	if _, ok := set.At(5); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+
+	if actualValue, ok := set.IndexOf("z"); actualValue != -1 || ok {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, ok, -1, false)
+	}
+}
+
+func TestSetFilterAndMap(t *testing.T) {
+	set := linkedhashset.NewFrom(5, 2, 8, 3, 4)
+
+	even := set.Filter(func(v int) bool { return v%2 == 0 })
+	if actualValue, expectedValue := even.Values(), []int{2, 8, 4}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue := set.Len(); actualValue != 5 {
+		t.Errorf("Filter should not modify the original set, got len %v", actualValue)
+	}
+
+	parity := linkedhashset.Map(set, func(v int) string {
+		if v%2 == 0 {
+			return "even"
+		}
+
+		return "odd"
+	})
+	if actualValue, expectedValue := parity.Values(), []string{"odd", "even"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue := linkedhashset.Map(linkedhashset.New[int](), strconv.Itoa); !actualValue.IsEmpty() {
+		t.Errorf("Got %v expected empty set", actualValue.Values())
+	}
+}
+
+func TestSetRetainAll(t *testing.T) {
+	set := linkedhashset.NewFrom(5, 2, 8, 3, 4)
+
+	set.RetainAll(linkedhashset.NewFrom(4, 9, 2, 3))
+	if actualValue, expectedValue := set.Values(), []int{2, 3, 4}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	set.RetainAll(linkedhashset.NewFrom(2, 3, 4))
+	if actualValue, expectedValue := set.Values(), []int{2, 3, 4}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	set.RetainAll(linkedhashset.New[int]())
+	if actualValue := set.Len(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	if actualValue := set.Add(2); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestSetPopBack(t *testing.T) {
+	set := linkedhashset.New[string]()
+
+	if v, ok := set.PopBack(); v != "" || ok {
+		t.Errorf("Got %v,%v expected %v,%v", v, ok, "", false)
+	}
+
+	set.Append("a", "b", "c")
+
+	if v, ok := set.PopBack(); v != "c" || !ok {
+		t.Errorf("Got %v,%v expected %v,%v", v, ok, "c", true)
+	}
+
+	set.Add("d")
+
+	if v, ok := set.Pop(); v != "a" || !ok {
+		t.Errorf("Got %v,%v expected %v,%v", v, ok, "a", true)
+	}
+
+	if v, ok := set.PopBack(); v != "d" || !ok {
+		t.Errorf("Got %v,%v expected %v,%v", v, ok, "d", true)
+	}
+
+	// Popped elements are gone from the table too, so they can be re-added.
+	if !set.Add("c") || set.Contains("a") || set.Contains("d") {
+		t.Errorf("PopBack and Pop should remove the element from the set")
+	}
+
+	if actualValue, expectedValue := set.Values(), []string{"b", "c"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	set.PopBack()
+	set.PopBack()
+
+	if v, ok := set.PopBack(); v != "" || ok || !set.IsEmpty() {
+		t.Errorf("Got %v,%v expected %v,%v", v, ok, "", false)
+	}
+}
+
+func TestSetBounded(t *testing.T) {
+	set := linkedhashset.NewBounded[int](3)
+
+	if actualValue := set.Append(1, 2, 3); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+
+	if evicted, ok := set.AddEvict(4); evicted != 1 || !ok {
+		t.Errorf("Got %v,%v expected %v,%v", evicted, ok, 1, true)
+	}
+
+	// Re-adding marks the element as most recent without evicting.
+	if evicted, ok := set.AddEvict(2); evicted != 0 || ok {
+		t.Errorf("Got %v,%v expected %v,%v", evicted, ok, 0, false)
+	}
+
+	if actualValue, expectedValue := set.Values(), []int{3, 4, 2}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if !set.Add(5) || set.Add(4) {
+		t.Errorf("Add should report whether the element was new")
+	}
+
+	if actualValue, expectedValue := set.Values(), []int{2, 5, 4}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue := set.Append(6, 7, 8, 9); actualValue != 4 || set.Len() != 3 {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, set.Len(), 4, 3)
+	}
+
+	clone := set.Clone().(*linkedhashset.Set[int])
+	clone.Add(10)
+
+	if clone.MaxSize() != 3 || clone.Len() != 3 || set.Contains(10) {
+		t.Errorf("Clone should keep the bound and not share state")
+	}
+
+	unbounded := linkedhashset.NewFrom(1, 2)
+	if unbounded.MaxSize() != 0 {
+		t.Errorf("Got %v expected %v", unbounded.MaxSize(), 0)
+	}
+
+	unbounded.Add(1)
+
+	if actualValue, expectedValue := unbounded.Values(), []int{1, 2}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Re-adding should not reorder an unbounded set, got %v", actualValue)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("NewBounded should panic on a non-positive size")
+		}
+	}()
+
+	linkedhashset.NewBounded[int](0)
+}
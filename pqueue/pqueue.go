@@ -15,6 +15,9 @@ import (
 	"container/heap"
 	"errors"
 	"fmt"
+	"iter"
+	"maps"
+	"slices"
 
 	"github.com/qntx/gods/cmp"
 	"github.com/qntx/gods/container"
@@ -37,12 +40,21 @@ const (
 
 // Item represents an element in the priority queue with a value and priority.
 type Item[T comparable, V any] struct {
-	index    int // index is used internally by the heap.Interface.
-	Value    T   // Value identifies the item.
-	Priority V   // Priority determines the item's order in the queue.
+	index    int    // index is used internally by the heap.Interface.
+	seq      uint64 // seq is the insertion order, used as a tie-breaker by stable queues.
+	Value    T      // Value identifies the item.
+	Priority V      // Priority determines the item's order in the queue.
+}
+
+// Index returns the item's current position in the heap slice, as seen by
+// UnsafeItems. It is only meaningful while the item is queued; items removed
+// by Dequeue, Remove or RemoveIf report -1.
+func (item *Item[T, V]) Index() int {
+	return item.index
 }
 
 var _ container.PQueue[int, int] = (*PriorityQueue[int, int])(nil)
+var _ container.Cloneable[*PriorityQueue[int, int]] = (*PriorityQueue[int, int])(nil)
 
 // var _ json.Marshaler = (*PriorityQueue[int, int])(nil)
 // var _ json.Unmarshaler = (*PriorityQueue[int, int])(nil)
@@ -51,11 +63,13 @@ var _ container.PQueue[int, int] = (*PriorityQueue[int, int])(nil)
 // It maintains items with associated priorities, supporting both min-heap and max-heap
 // configurations. The queue uses a map for O(1) value lookups and supports custom
 // comparators for priority ordering.
-type PriorityQueue[T comparable, V cmp.Ordered] struct {
-	kind HeapKind
-	heap []*Item[T, V]
-	idx  map[T]*Item[T, V]
-	cmp  cmp.Comparator[V]
+type PriorityQueue[T comparable, V any] struct {
+	kind   HeapKind
+	heap   []*Item[T, V]
+	idx    map[T]*Item[T, V]
+	cmp    cmp.Comparator[V]
+	stable bool   // stable breaks priority ties by insertion order.
+	seq    uint64 // seq is the sequence number assigned to the next new item.
 }
 
 // New creates a new priority queue with the default comparator for ordered types.
@@ -80,6 +94,7 @@ func New[T comparable, V cmp.Ordered](kind HeapKind) *PriorityQueue[T, V] {
 
 // NewWith creates a new priority queue with a custom comparator for priorities.
 // It initializes an empty queue with the specified heap kind and comparator.
+// As the comparator defines the order, V need not be an ordered type.
 //
 // Args:
 //
@@ -94,7 +109,7 @@ func New[T comparable, V cmp.Ordered](kind HeapKind) *PriorityQueue[T, V] {
 //
 //	pq := NewWith[string, int](MaxHeap, cmp.Compare[int])
 //	pq.Put("task1", 5)
-func NewWith[T comparable, V cmp.Ordered](kind HeapKind, cmp cmp.Comparator[V]) *PriorityQueue[T, V] {
+func NewWith[T comparable, V any](kind HeapKind, cmp cmp.Comparator[V]) *PriorityQueue[T, V] {
 	pq := &PriorityQueue[T, V]{
 		kind: kind,
 		heap: make([]*Item[T, V], 0, defaultCapacity), // Pre-allocate for efficiency.
@@ -106,6 +121,27 @@ func NewWith[T comparable, V cmp.Ordered](kind HeapKind, cmp cmp.Comparator[V])
 	return pq
 }
 
+// NewStable creates a new priority queue, like New, whose items with equal
+// priorities are dequeued in the order they were first enqueued (FIFO).
+//
+// A plain queue yields equal-priority items in an arbitrary order; a stable
+// one records a sequence number on each new item and uses it to break ties
+// in Less, which makes scheduling deterministic. Changing the priority of an
+// existing item with Set or Enqueue keeps its original sequence number.
+//
+// Example:
+//
+//	pq := NewStable[string, int](MinHeap)
+//	pq.Enqueue("a", 1)
+//	pq.Enqueue("b", 1)
+//	v, _, _ := pq.Dequeue() // "a"
+func NewStable[T comparable, V cmp.Ordered](kind HeapKind) *PriorityQueue[T, V] {
+	pq := New[T, V](kind)
+	pq.stable = true
+
+	return pq
+}
+
 // Len returns the number of items in the queue.
 // Time complexity: O(1).
 func (pq *PriorityQueue[T, V]) Len() int {
@@ -116,6 +152,9 @@ func (pq *PriorityQueue[T, V]) Len() int {
 // Time complexity: O(1).
 func (pq *PriorityQueue[T, V]) Less(i, j int) bool {
 	c := pq.cmp(pq.heap[i].Priority, pq.heap[j].Priority)
+	if c == 0 && pq.stable {
+		return pq.heap[i].seq < pq.heap[j].seq
+	}
 
 	return (pq.kind == MinHeap && c < 0) || (pq.kind == MaxHeap && c > 0)
 }
@@ -152,6 +191,7 @@ func (pq *PriorityQueue[T, V]) Pop() any {
 	item := pq.heap[n-1]
 	pq.heap = pq.heap[:n-1]
 	delete(pq.idx, item.Value)
+	item.index = -1 // For safety, in case the item is still referenced.
 
 	return item
 }
@@ -168,9 +208,11 @@ func (pq *PriorityQueue[T, V]) Enqueue(value T, priority V) {
 	}
 
 	item := &Item[T, V]{
+		seq:      pq.seq,
 		Value:    value,
 		Priority: priority,
 	}
+	pq.seq++
 	heap.Push(pq, item)
 }
 
@@ -190,6 +232,28 @@ func (pq *PriorityQueue[T, V]) Dequeue() (value T, priority V, ok bool) {
 	return item.(*Item[T, V]).Value, item.(*Item[T, V]).Priority, true
 }
 
+// ExpireBefore removes and returns, in priority order, every value whose
+// priority is less than or equal to threshold. It stops at the first item
+// beyond the threshold, so only the expired items are touched.
+//
+// Intended for min-heaps keyed on deadlines (e.g. time.Time in Unix nanos).
+// Panics if the queue is not a MinHeap, as the root would not be the earliest.
+// Time complexity: O(k log n) for k expired items.
+func (pq *PriorityQueue[T, V]) ExpireBefore(threshold V) []T {
+	if pq.kind != MinHeap {
+		panic("pqueue: ExpireBefore requires a MinHeap")
+	}
+
+	var expired []T
+
+	for len(pq.heap) > 0 && pq.cmp(pq.heap[0].Priority, threshold) <= 0 {
+		value, _, _ := pq.Dequeue()
+		expired = append(expired, value)
+	}
+
+	return expired
+}
+
 // Peek returns the item with the highest/lowest priority, based on the heap kind.
 // Returns nil if the queue is empty.
 // Time complexity: O(1).
@@ -201,6 +265,27 @@ func (pq *PriorityQueue[T, V]) Peek() (value T, priority V, ok bool) {
 	return pq.heap[0].Value, pq.heap[0].Priority, true
 }
 
+// Contains checks if the value is present in the queue.
+// Time complexity: O(1).
+func (pq *PriorityQueue[T, V]) Contains(value T) bool {
+	_, exists := pq.idx[value]
+
+	return exists
+}
+
+// ItemOf returns the item holding value, or nil and false if the value is
+// not in the queue. The item stays current while it is queued: its Priority
+// and Index reflect later Set calls and heap reorderings.
+//
+// The item is a read-only handle. Modifying its fields directly corrupts the
+// queue; use Set to change its priority.
+// Time complexity: O(1).
+func (pq *PriorityQueue[T, V]) ItemOf(value T) (*Item[T, V], bool) {
+	item, exists := pq.idx[value]
+
+	return item, exists
+}
+
 // Set changes the priority of an existing value in the queue.
 //
 // Time complexity: O(log n).
@@ -231,6 +316,82 @@ func (pq *PriorityQueue[T, V]) Remove(value T) bool {
 	return true
 }
 
+// RemoveIf removes every item for which pred returns true and returns how
+// many were removed. The heap is compacted and re-heapified once, rather
+// than fixed after each removal.
+// Time complexity: O(n).
+func (pq *PriorityQueue[T, V]) RemoveIf(pred func(value T, priority V) bool) int {
+	kept := pq.heap[:0]
+
+	for _, item := range pq.heap {
+		if pred(item.Value, item.Priority) {
+			delete(pq.idx, item.Value)
+			item.index = -1
+
+			continue
+		}
+
+		item.index = len(kept)
+		kept = append(kept, item)
+	}
+
+	removed := len(pq.heap) - len(kept)
+	clear(pq.heap[len(kept):]) // Release removed items for garbage collection.
+	pq.heap = kept
+
+	if removed > 0 {
+		heap.Init(pq)
+	}
+
+	return removed
+}
+
+// RemoveAndGet removes the item with the specified value from the queue and
+// returns the priority it had.
+// Returns the priority and true if the item was removed, or the zero value and
+// false if the value is not present.
+// Time complexity: O(log n).
+func (pq *PriorityQueue[T, V]) RemoveAndGet(value T) (priority V, ok bool) {
+	item, exists := pq.idx[value]
+	if !exists {
+		return priority, false
+	}
+
+	heap.Remove(pq, item.index)
+
+	return item.Priority, true
+}
+
+// SetKind switches the queue between MinHeap and MaxHeap ordering and
+// re-heapifies the existing items in place. Items and their value lookups are
+// left intact; only the order in which Dequeue and Peek yield them changes,
+// and it does so immediately after the call.
+// Time complexity: O(n).
+func (pq *PriorityQueue[T, V]) SetKind(kind HeapKind) {
+	if pq.kind == kind {
+		return
+	}
+
+	pq.kind = kind
+	heap.Init(pq)
+}
+
+// Reserve grows the queue's internal storage, if needed, so that it can hold
+// n items in total without reallocating during subsequent Enqueues. It does
+// not change Len or the order of the items.
+// Time complexity: O(len) when the storage grows, O(1) otherwise.
+func (pq *PriorityQueue[T, V]) Reserve(n int) {
+	if n <= cap(pq.heap) {
+		return
+	}
+
+	pq.heap = slices.Grow(pq.heap, n-len(pq.heap))
+
+	idx := make(map[T]*Item[T, V], n)
+	maps.Copy(idx, pq.idx)
+	pq.idx = idx
+}
+
 // Clear removes all items from the queue and resets its internal state.
 // Time complexity: O(1).
 func (pq *PriorityQueue[T, V]) Clear() {
@@ -245,6 +406,65 @@ func (pq *PriorityQueue[T, V]) IsEmpty() bool {
 	return len(pq.heap) == 0
 }
 
+// Clone returns a deep copy of the queue with the same heap kind and
+// comparator. Items are copied, so modifying the clone or its items does not
+// affect the original.
+// Time complexity: O(n).
+func (pq *PriorityQueue[T, V]) Clone() *PriorityQueue[T, V] {
+	clone := &PriorityQueue[T, V]{
+		kind:   pq.kind,
+		heap:   make([]*Item[T, V], len(pq.heap), max(cap(pq.heap), defaultCapacity)),
+		idx:    make(map[T]*Item[T, V], max(len(pq.heap), defaultCapacity)),
+		cmp:    pq.cmp,
+		stable: pq.stable,
+		seq:    pq.seq,
+	}
+
+	for i, item := range pq.heap {
+		c := *item
+		clone.heap[i] = &c
+		clone.idx[c.Value] = &c
+	}
+
+	return clone
+}
+
+// IsValidHeap reports whether every item in the heap slice is ordered
+// correctly relative to its parent according to Less, i.e. no child would
+// be dequeued before its parent.
+//
+// Intended as a testing aid: a false result usually means the comparator is
+// inconsistent or an item's Priority was modified without calling Set.
+// Time complexity: O(n).
+func (pq *PriorityQueue[T, V]) IsValidHeap() bool {
+	for i := 1; i < len(pq.heap); i++ {
+		if pq.Less(i, (i-1)/2) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// VerifyIndexMap reports whether every item's index matches its position in
+// the heap slice and the value lookup map holds exactly the items in the heap.
+//
+// Intended as a testing aid alongside IsValidHeap.
+// Time complexity: O(n).
+func (pq *PriorityQueue[T, V]) VerifyIndexMap() bool {
+	if len(pq.idx) != len(pq.heap) {
+		return false
+	}
+
+	for i, item := range pq.heap {
+		if item.index != i || pq.idx[item.Value] != item {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Values returns a copy of the values in the queue.
 // This is a safe operation that doesn't expose the internal heap structure.
 // Time complexity: O(n).
@@ -264,6 +484,22 @@ func (pq *PriorityQueue[T, V]) ToSlice() []T {
 	return pq.Values()
 }
 
+// Iter returns an iterator over the (value, priority) pairs in the queue.
+//
+// Items are yielded in heap-array order, which is NOT priority order: only the
+// first pair is guaranteed to be the one Peek would return. Use Dequeue for
+// priority order. The queue must not be modified during iteration.
+// Time complexity: O(n) for a full traversal, with no allocation.
+func (pq *PriorityQueue[T, V]) Iter() iter.Seq2[T, V] {
+	return func(yield func(T, V) bool) {
+		for _, item := range pq.heap {
+			if !yield(item.Value, item.Priority) {
+				return
+			}
+		}
+	}
+}
+
 // Items returns a copy of the heap slice containing all queue items.
 // This is a safe operation that doesn't expose the internal heap structure.
 // Time complexity: O(n).
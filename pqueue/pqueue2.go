@@ -0,0 +1,95 @@
+package pqueue
+
+import (
+	"github.com/qntx/gods/cmp"
+)
+
+// PriorityQueue2 is a priority queue whose items carry two priorities: a
+// primary one, and a secondary one that breaks ties between equal primaries,
+// e.g. an arrival time for "priority, then FIFO" scheduling.
+//
+// The heap kind applies to the combined order: a MinHeap yields the smallest
+// primary first and, among equal primaries, the smallest secondary.
+type PriorityQueue2[T comparable, V1, V2 any] struct {
+	pq *PriorityQueue[T, cmp.Pair[V1, V2]]
+}
+
+// NewWith2 creates a new two-key priority queue ordered by c1 on the primary
+// priorities, then by c2 on the secondary ones.
+//
+// Example:
+//
+//	pq := NewWith2[string](MinHeap, cmp.Compare[int], cmp.Compare[int64])
+//	pq.Enqueue("task1", 1, time.Now().UnixNano())
+func NewWith2[T comparable, V1, V2 any](kind HeapKind, c1 cmp.Comparator[V1], c2 cmp.Comparator[V2]) *PriorityQueue2[T, V1, V2] {
+	return &PriorityQueue2[T, V1, V2]{pq: NewWith[T](kind, cmp.PairComparator(c1, c2))}
+}
+
+// Enqueue adds a value with the specified priorities to the queue.
+// If the value already exists, it updates both priorities.
+// Time complexity: O(log n).
+func (pq *PriorityQueue2[T, V1, V2]) Enqueue(value T, p1 V1, p2 V2) {
+	pq.pq.Enqueue(value, cmp.Pair[V1, V2]{First: p1, Second: p2})
+}
+
+// Dequeue removes and returns the first item in priority order, with its
+// priorities. Returns zero values and false if the queue is empty.
+// Time complexity: O(log n).
+func (pq *PriorityQueue2[T, V1, V2]) Dequeue() (value T, p1 V1, p2 V2, ok bool) {
+	value, p, ok := pq.pq.Dequeue()
+
+	return value, p.First, p.Second, ok
+}
+
+// Peek returns the first item in priority order, with its priorities, without
+// removing it. Returns zero values and false if the queue is empty.
+// Time complexity: O(1).
+func (pq *PriorityQueue2[T, V1, V2]) Peek() (value T, p1 V1, p2 V2, ok bool) {
+	value, p, ok := pq.pq.Peek()
+
+	return value, p.First, p.Second, ok
+}
+
+// Set changes both priorities of an existing value in the queue.
+// Returns false if the value is not present.
+// Time complexity: O(log n).
+func (pq *PriorityQueue2[T, V1, V2]) Set(value T, p1 V1, p2 V2) bool {
+	return pq.pq.Set(value, cmp.Pair[V1, V2]{First: p1, Second: p2})
+}
+
+// Remove removes the item with the specified value from the queue.
+// Returns true if the item was removed, false otherwise.
+// Time complexity: O(log n).
+func (pq *PriorityQueue2[T, V1, V2]) Remove(value T) bool {
+	return pq.pq.Remove(value)
+}
+
+// Contains checks if the value is present in the queue.
+// Time complexity: O(1).
+func (pq *PriorityQueue2[T, V1, V2]) Contains(value T) bool {
+	return pq.pq.Contains(value)
+}
+
+// Len returns the number of items in the queue.
+// Time complexity: O(1).
+func (pq *PriorityQueue2[T, V1, V2]) Len() int {
+	return pq.pq.Len()
+}
+
+// IsEmpty checks if the queue contains no items.
+// Time complexity: O(1).
+func (pq *PriorityQueue2[T, V1, V2]) IsEmpty() bool {
+	return pq.pq.IsEmpty()
+}
+
+// Clear removes all items from the queue.
+// Time complexity: O(1).
+func (pq *PriorityQueue2[T, V1, V2]) Clear() {
+	pq.pq.Clear()
+}
+
+// Values returns a copy of the values in the queue, in heap order.
+// Time complexity: O(n).
+func (pq *PriorityQueue2[T, V1, V2]) Values() []T {
+	return pq.pq.Values()
+}
@@ -1,9 +1,16 @@
 package pqueue_test
 
 import (
+	"context"
+	"errors"
 	"math/rand"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/qntx/gods/cmp"
+	"github.com/qntx/gods/container"
 	"github.com/qntx/gods/pqueue"
 )
 
@@ -275,3 +282,540 @@ func TestPriorityQueuePeek(t *testing.T) {
 // 	}
 // 	assert()
 // }
+
+func TestPriorityQueueContains(t *testing.T) {
+	var queue container.PQueue[string, int] = pqueue.New[string, int](pqueue.MinHeap)
+
+	if queue.Contains("a") {
+		t.Errorf("Empty queue should not contain %q", "a")
+	}
+
+	queue.Enqueue("a", 1)
+	queue.Enqueue("b", 2)
+
+	if !queue.Contains("a") || !queue.Contains("b") {
+		t.Errorf("Queue should contain %q and %q", "a", "b")
+	}
+
+	if queue.Len() != 2 {
+		t.Errorf("Got %v expected %v", queue.Len(), 2)
+	}
+
+	queue.Dequeue()
+
+	if queue.Contains("a") {
+		t.Errorf("Queue should not contain %q after dequeue", "a")
+	}
+}
+
+func TestSyncPriorityQueue(t *testing.T) {
+	queue := pqueue.NewSync[int, int](pqueue.MinHeap)
+
+	var wg sync.WaitGroup
+
+	for i := range 100 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			queue.Enqueue(i, i)
+		}()
+	}
+
+	wg.Wait()
+
+	if actualValue := queue.Len(); actualValue != 100 {
+		t.Errorf("Got %v expected %v", actualValue, 100)
+	}
+
+	for i := range 100 {
+		v, p, ok := queue.Dequeue()
+		if !ok || v != i || p != i {
+			t.Errorf("Got %v,%v,%v expected %v,%v,%v", v, p, ok, i, i, true)
+		}
+	}
+}
+
+func TestSyncPriorityQueueDequeueWait(t *testing.T) {
+	queue := pqueue.NewSync[string, int](pqueue.MinHeap)
+
+	done := make(chan string)
+
+	go func() {
+		v, _, err := queue.DequeueWait(context.Background())
+		if err != nil {
+			t.Errorf("Got error %v", err)
+		}
+
+		done <- v
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	queue.Enqueue("a", 1)
+
+	select {
+	case v := <-done:
+		if v != "a" {
+			t.Errorf("Got %v expected %v", v, "a")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("DequeueWait did not return after Enqueue")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := queue.DequeueWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Got %v expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestPriorityQueueSetKind(t *testing.T) {
+	queue := pqueue.New[int, int](pqueue.MinHeap)
+	for i := range 10 {
+		queue.Enqueue(i, i)
+	}
+
+	if value, _, _ := queue.Peek(); value != 0 {
+		t.Errorf("Got %v expected %v", value, 0)
+	}
+
+	queue.SetKind(pqueue.MaxHeap)
+
+	for expected := 9; expected >= 0; expected-- {
+		value, priority, ok := queue.Dequeue()
+		if !ok || value != expected || priority != expected {
+			t.Errorf("Got %v,%v,%v expected %v,%v,%v", value, priority, ok, expected, expected, true)
+		}
+
+		if expected == 5 {
+			queue.SetKind(pqueue.MinHeap)
+
+			for want := range 5 {
+				if value, _, _ := queue.Dequeue(); value != want {
+					t.Errorf("Got %v expected %v", value, want)
+				}
+			}
+
+			break
+		}
+	}
+
+	if !queue.IsEmpty() {
+		t.Errorf("Got %v expected %v", queue.Len(), 0)
+	}
+}
+
+func TestPriorityQueueRemoveAndGet(t *testing.T) {
+	queue := pqueue.New[string, int](pqueue.MinHeap)
+	queue.Enqueue("a", 3)
+	queue.Enqueue("b", 1)
+	queue.Enqueue("c", 2)
+
+	if priority, ok := queue.RemoveAndGet("c"); priority != 2 || !ok {
+		t.Errorf("Got %v,%v expected %v,%v", priority, ok, 2, true)
+	}
+
+	if priority, ok := queue.RemoveAndGet("c"); priority != 0 || ok {
+		t.Errorf("Got %v,%v expected %v,%v", priority, ok, 0, false)
+	}
+
+	if queue.Contains("c") || queue.Len() != 2 {
+		t.Errorf("Queue should no longer contain %q", "c")
+	}
+
+	for _, expected := range []string{"b", "a"} {
+		if value, _, _ := queue.Dequeue(); value != expected {
+			t.Errorf("Got %v expected %v", value, expected)
+		}
+	}
+}
+
+func TestPriorityQueueIter(t *testing.T) {
+	queue := pqueue.New[string, int](pqueue.MaxHeap)
+
+	for range queue.Iter() {
+		t.Errorf("Shouldn't iterate on empty queue")
+	}
+
+	expected := map[string]int{"a": 1, "b": 5, "c": 3, "d": 4}
+	for v, p := range expected {
+		queue.Enqueue(v, p)
+	}
+
+	seen := map[string]int{}
+	first := true
+
+	for v, p := range queue.Iter() {
+		if first && v != "b" {
+			t.Errorf("First yielded value %v should be the heap root %v", v, "b")
+		}
+
+		first = false
+		seen[v] = p
+	}
+
+	if len(seen) != len(expected) {
+		t.Errorf("Got %v expected %v", seen, expected)
+	}
+
+	for v, p := range expected {
+		if seen[v] != p {
+			t.Errorf("Got %v expected %v for %v", seen[v], p, v)
+		}
+	}
+
+	count := 0
+	for range queue.Iter() {
+		count++
+
+		break
+	}
+
+	if count != 1 || queue.Len() != len(expected) {
+		t.Errorf("Iteration should stop early and not drain the queue")
+	}
+}
+
+func TestPriorityQueueExpireBefore(t *testing.T) {
+	queue := pqueue.New[string, int64](pqueue.MinHeap)
+
+	if actualValue := queue.ExpireBefore(100); len(actualValue) != 0 {
+		t.Errorf("Got %v expected none", actualValue)
+	}
+
+	base := time.Unix(1000, 0)
+	queue.Enqueue("c", base.Add(3*time.Second).UnixNano())
+	queue.Enqueue("a", base.Add(1*time.Second).UnixNano())
+	queue.Enqueue("d", base.Add(4*time.Second).UnixNano())
+	queue.Enqueue("b", base.Add(2*time.Second).UnixNano())
+
+	expired := queue.ExpireBefore(base.Add(3 * time.Second).UnixNano())
+	if actualValue, expectedValue := expired, []string{"a", "b", "c"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if value, _, _ := queue.Peek(); queue.Len() != 1 || value != "d" {
+		t.Errorf("Got %v expected %v", value, "d")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("ExpireBefore should panic on a MaxHeap")
+		}
+	}()
+
+	pqueue.New[string, int](pqueue.MaxHeap).ExpireBefore(0)
+}
+
+func TestPriorityQueueClone(t *testing.T) {
+	queue := pqueue.New[int, int](pqueue.MaxHeap)
+	for _, v := range []int{5, 1, 9, 3, 7} {
+		queue.Enqueue(v, v)
+	}
+
+	clone := queue.Clone()
+	clone.Set(1, 100)
+	clone.Enqueue(42, 42)
+
+	for _, expected := range []int{1, 42, 9, 7, 5, 3} {
+		if value, _, ok := clone.Dequeue(); !ok || value != expected {
+			t.Errorf("Clone: got %v,%v expected %v,%v", value, ok, expected, true)
+		}
+	}
+
+	if !clone.IsEmpty() {
+		t.Errorf("Clone should be drained")
+	}
+
+	for _, expected := range []int{9, 7, 5, 3, 1} {
+		value, priority, ok := queue.Dequeue()
+		if !ok || value != expected || priority != expected {
+			t.Errorf("Original: got %v,%v,%v expected %v,%v,%v", value, priority, ok, expected, expected, true)
+		}
+	}
+
+	if !queue.IsEmpty() {
+		t.Errorf("Original should be drained")
+	}
+}
+
+func TestPriorityQueueStable(t *testing.T) {
+	for _, kind := range []pqueue.HeapKind{pqueue.MinHeap, pqueue.MaxHeap} {
+		queue := pqueue.NewStable[int, int](kind)
+
+		// 100 items spread over 3 priorities, interleaved.
+		for i := range 100 {
+			queue.Enqueue(i, i%3)
+		}
+
+		var got []int
+
+		for !queue.IsEmpty() {
+			value, _, _ := queue.Dequeue()
+			got = append(got, value)
+		}
+
+		var expected []int
+
+		priorities := []int{0, 1, 2}
+		if kind == pqueue.MaxHeap {
+			slices.Reverse(priorities)
+		}
+
+		for _, p := range priorities {
+			for i := p; i < 100; i += 3 {
+				expected = append(expected, i)
+			}
+		}
+
+		if !slices.Equal(got, expected) {
+			t.Errorf("kind=%v: got %v expected %v", kind, got, expected)
+		}
+	}
+
+	// Updating a priority keeps the item's original place among equals.
+	queue := pqueue.NewStable[string, int](pqueue.MinHeap)
+	queue.Enqueue("a", 5)
+	queue.Enqueue("b", 1)
+	queue.Enqueue("c", 1)
+	queue.Set("a", 1)
+
+	clone := queue.Clone()
+	clone.Enqueue("d", 1)
+
+	for _, expected := range []string{"a", "b", "c", "d"} {
+		if value, _, _ := clone.Dequeue(); value != expected {
+			t.Errorf("Got %v expected %v", value, expected)
+		}
+	}
+}
+
+func TestPriorityQueueValidation(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	for _, kind := range []pqueue.HeapKind{pqueue.MinHeap, pqueue.MaxHeap} {
+		queue := pqueue.New[int, int](kind)
+
+		for i := range 1000 {
+			switch rng.Intn(4) {
+			case 0, 1:
+				queue.Enqueue(rng.Intn(100), rng.Intn(50))
+			case 2:
+				queue.Set(rng.Intn(100), rng.Intn(50))
+			default:
+				queue.Dequeue()
+			}
+
+			if !queue.IsValidHeap() || !queue.VerifyIndexMap() {
+				t.Fatalf("kind=%v step=%v: heap or index map invalid", kind, i)
+			}
+		}
+	}
+
+	queue := pqueue.New[string, int](pqueue.MinHeap)
+	for i, v := range []string{"a", "b", "c", "d"} {
+		queue.Enqueue(v, i)
+	}
+
+	// Mutating a priority behind the queue's back breaks the heap property.
+	queue.UnsafeItems()[3].Priority = -1
+
+	if queue.IsValidHeap() {
+		t.Errorf("IsValidHeap should detect a corrupted priority")
+	}
+
+	if !queue.VerifyIndexMap() {
+		t.Errorf("VerifyIndexMap should be unaffected by a corrupted priority")
+	}
+
+	items := queue.UnsafeItems()
+	items[0], items[1] = items[1], items[0]
+
+	if queue.VerifyIndexMap() {
+		t.Errorf("VerifyIndexMap should detect stale indices")
+	}
+}
+
+func TestPriorityQueueReserve(t *testing.T) {
+	queue := pqueue.New[int, int](pqueue.MinHeap)
+	for _, v := range []int{5, 1, 3} {
+		queue.Enqueue(v, v)
+	}
+
+	queue.Reserve(1000)
+
+	if actualValue := cap(queue.UnsafeItems()); actualValue < 1000 {
+		t.Errorf("Got capacity %v expected at least %v", actualValue, 1000)
+	}
+
+	if actualValue, expectedValue := queue.Len(), 3; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if !queue.VerifyIndexMap() {
+		t.Errorf("Reserve should keep the index map consistent")
+	}
+
+	items := queue.UnsafeItems()
+
+	for i := range 997 {
+		queue.Enqueue(i+10, i+10)
+	}
+
+	if &items[0] != &queue.UnsafeItems()[0] {
+		t.Errorf("Enqueue should not reallocate after Reserve")
+	}
+
+	for _, expected := range []int{1, 3, 5, 10} {
+		if value, _, _ := queue.Dequeue(); value != expected {
+			t.Errorf("Got %v expected %v", value, expected)
+		}
+	}
+
+	// Reserving less than the current capacity is a no-op.
+	queue.Reserve(1)
+
+	if actualValue, expectedValue := queue.Len(), 996; actualValue != expectedValue {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestPriorityQueueRemoveIf(t *testing.T) {
+	queue := pqueue.New[int, int](pqueue.MinHeap)
+	for i := range 100 {
+		queue.Enqueue(i, (i*37)%100)
+	}
+
+	if actualValue := queue.RemoveIf(func(int, int) bool { return false }); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	if actualValue := queue.RemoveIf(func(v, _ int) bool { return v%3 == 0 }); actualValue != 34 {
+		t.Errorf("Got %v expected %v", actualValue, 34)
+	}
+
+	if !queue.IsValidHeap() || !queue.VerifyIndexMap() {
+		t.Fatalf("RemoveIf left the heap or index map invalid")
+	}
+
+	if queue.Contains(3) || !queue.Contains(4) {
+		t.Errorf("RemoveIf removed the wrong values")
+	}
+
+	last := -1
+
+	for !queue.IsEmpty() {
+		value, priority, _ := queue.Dequeue()
+		if value%3 == 0 {
+			t.Errorf("Dequeued removed value %v", value)
+		}
+
+		if priority < last {
+			t.Errorf("Got priority %v after %v", priority, last)
+		}
+
+		last = priority
+	}
+
+	queue.Enqueue(1, 1)
+
+	if actualValue := queue.RemoveIf(func(_, p int) bool { return p == 1 }); actualValue != 1 || !queue.IsEmpty() {
+		t.Errorf("Got %v removed, len %v expected 1 removed and an empty queue", actualValue, queue.Len())
+	}
+}
+
+func TestPriorityQueue2(t *testing.T) {
+	// Priority first, then arrival order.
+	queue := pqueue.NewWith2[string](pqueue.MinHeap, cmp.Compare[int], cmp.Compare[int])
+
+	for i, task := range []struct {
+		name     string
+		priority int
+	}{{"a", 2}, {"b", 1}, {"c", 2}, {"d", 1}, {"e", 0}} {
+		queue.Enqueue(task.name, task.priority, i)
+	}
+
+	if value, p1, p2, ok := queue.Peek(); value != "e" || p1 != 0 || p2 != 4 || !ok {
+		t.Errorf("Got %v,%v,%v,%v expected %v,%v,%v,%v", value, p1, p2, ok, "e", 0, 4, true)
+	}
+
+	// Moving "c" ahead of "a" within priority 2.
+	if !queue.Set("c", 2, -1) || queue.Set("x", 0, 0) {
+		t.Errorf("Set should report whether the value was present")
+	}
+
+	var order []string
+
+	for !queue.IsEmpty() {
+		value, _, _, _ := queue.Dequeue()
+		order = append(order, value)
+	}
+
+	if expected := []string{"e", "b", "d", "c", "a"}; !slices.Equal(order, expected) {
+		t.Errorf("Got %v expected %v", order, expected)
+	}
+
+	if value, p1, p2, ok := queue.Dequeue(); value != "" || p1 != 0 || p2 != 0 || ok {
+		t.Errorf("Got %v,%v,%v,%v expected zero values and false", value, p1, p2, ok)
+	}
+
+	maxQueue := pqueue.NewWith2[int](pqueue.MaxHeap, cmp.Compare[string], cmp.Compare[float64])
+	maxQueue.Enqueue(1, "x", 1.5)
+	maxQueue.Enqueue(2, "x", 2.5)
+	maxQueue.Enqueue(3, "a", 9)
+
+	if !maxQueue.Remove(3) || maxQueue.Contains(3) || maxQueue.Len() != 2 {
+		t.Errorf("Remove should drop the value")
+	}
+
+	if value, p1, p2, _ := maxQueue.Dequeue(); value != 2 || p1 != "x" || p2 != 2.5 {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", value, p1, p2, 2, "x", 2.5)
+	}
+}
+
+func TestPriorityQueueItemOf(t *testing.T) {
+	queue := pqueue.New[string, int](pqueue.MinHeap)
+
+	if item, ok := queue.ItemOf("a"); item != nil || ok {
+		t.Errorf("Got %v,%v expected %v,%v", item, ok, nil, false)
+	}
+
+	for i, v := range []string{"a", "b", "c", "d"} {
+		queue.Enqueue(v, 10-i)
+	}
+
+	item, ok := queue.ItemOf("b")
+	if !ok || item.Value != "b" || item.Priority != 9 {
+		t.Fatalf("Got %v,%v expected item b with priority 9", item, ok)
+	}
+
+	if actualValue := queue.UnsafeItems()[item.Index()]; actualValue != item {
+		t.Errorf("Got %v expected %v", actualValue, item)
+	}
+
+	// The handle follows priority changes and heap moves.
+	queue.Set("b", 0)
+
+	if item.Priority != 0 || item.Index() != 0 {
+		t.Errorf("Got priority %v index %v expected %v,%v", item.Priority, item.Index(), 0, 0)
+	}
+
+	queue.Remove("b")
+
+	if item.Index() != -1 {
+		t.Errorf("Got %v expected %v", item.Index(), -1)
+	}
+
+	if _, ok := queue.ItemOf("b"); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+
+	removed, _ := queue.ItemOf("c")
+	queue.RemoveIf(func(v string, _ int) bool { return v == "c" })
+
+	if removed.Index() != -1 || !queue.VerifyIndexMap() {
+		t.Errorf("Got %v expected %v", removed.Index(), -1)
+	}
+}
@@ -0,0 +1,165 @@
+package pqueue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/qntx/gods/cmp"
+)
+
+// SyncPriorityQueue is a concurrency-safe wrapper around PriorityQueue.
+// All operations are serialized by a mutex, and DequeueWait allows consumers
+// to block until an item becomes available, making it suitable as a shared
+// work queue between goroutines.
+type SyncPriorityQueue[T comparable, V cmp.Ordered] struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	pq   *PriorityQueue[T, V]
+}
+
+// NewSync creates a new concurrency-safe priority queue with the default
+// comparator for ordered types.
+//
+// Example:
+//
+//	pq := NewSync[string, int](MinHeap)
+//	go pq.Enqueue("task", 1)
+//	v, p, err := pq.DequeueWait(ctx)
+func NewSync[T comparable, V cmp.Ordered](kind HeapKind) *SyncPriorityQueue[T, V] {
+	return NewSyncWith[T](kind, cmp.Compare[V])
+}
+
+// NewSyncWith creates a new concurrency-safe priority queue with a custom
+// comparator for priorities.
+func NewSyncWith[T comparable, V cmp.Ordered](kind HeapKind, cmp cmp.Comparator[V]) *SyncPriorityQueue[T, V] {
+	s := &SyncPriorityQueue[T, V]{pq: NewWith[T](kind, cmp)}
+	s.cond = sync.NewCond(&s.mu)
+
+	return s
+}
+
+// Enqueue adds a value with the specified priority to the queue, or updates
+// its priority if it already exists, and wakes one waiting consumer.
+// Time complexity: O(log n).
+func (s *SyncPriorityQueue[T, V]) Enqueue(value T, priority V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pq.Enqueue(value, priority)
+	s.cond.Signal()
+}
+
+// Dequeue removes and returns the item with the highest/lowest priority without
+// blocking. Returns false if the queue is empty.
+// Time complexity: O(log n).
+func (s *SyncPriorityQueue[T, V]) Dequeue() (value T, priority V, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pq.Dequeue()
+}
+
+// DequeueWait removes and returns the item with the highest/lowest priority,
+// blocking until an item is available or ctx is done.
+// Returns ctx.Err() if the context is cancelled before an item is dequeued.
+// Time complexity: O(log n) once an item is available.
+func (s *SyncPriorityQueue[T, V]) DequeueWait(ctx context.Context) (value T, priority V, err error) {
+	// Wake all waiters on cancellation so they can observe ctx.Err().
+	stop := context.AfterFunc(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.cond.Broadcast()
+	})
+	defer stop()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.pq.IsEmpty() {
+		if err = ctx.Err(); err != nil {
+			return value, priority, err
+		}
+
+		s.cond.Wait()
+	}
+
+	value, priority, _ = s.pq.Dequeue()
+
+	return value, priority, nil
+}
+
+// Peek returns the item with the highest/lowest priority without removing it.
+// Time complexity: O(1).
+func (s *SyncPriorityQueue[T, V]) Peek() (value T, priority V, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pq.Peek()
+}
+
+// Set changes the priority of an existing value in the queue.
+// Returns false if the value is not present.
+// Time complexity: O(log n).
+func (s *SyncPriorityQueue[T, V]) Set(value T, priority V) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pq.Set(value, priority)
+}
+
+// Remove removes the item with the specified value from the queue.
+// Returns true if the item was removed, false otherwise.
+// Time complexity: O(log n).
+func (s *SyncPriorityQueue[T, V]) Remove(value T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pq.Remove(value)
+}
+
+// RemoveIf removes every item for which pred returns true and returns how
+// many were removed. pred is called with the lock held.
+// Time complexity: O(n).
+func (s *SyncPriorityQueue[T, V]) RemoveIf(pred func(value T, priority V) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pq.RemoveIf(pred)
+}
+
+// Contains checks if the value is present in the queue.
+// Time complexity: O(1).
+func (s *SyncPriorityQueue[T, V]) Contains(value T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pq.Contains(value)
+}
+
+// Len returns the number of items in the queue.
+// Time complexity: O(1).
+func (s *SyncPriorityQueue[T, V]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pq.Len()
+}
+
+// IsEmpty checks if the queue contains no items.
+// Time complexity: O(1).
+func (s *SyncPriorityQueue[T, V]) IsEmpty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pq.IsEmpty()
+}
+
+// Clear removes all items from the queue.
+// Time complexity: O(1).
+func (s *SyncPriorityQueue[T, V]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pq.Clear()
+}
@@ -8,6 +8,7 @@ package rbtree
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"iter"
 	"maps"
@@ -17,6 +18,9 @@ import (
 	"github.com/qntx/gods/container"
 )
 
+// ErrInvalidTree is wrapped by the errors returned from VerifyProperties.
+var ErrInvalidTree = errors.New("rbtree: invalid tree")
+
 // Color represents the color of a red-black tree node (red or black).
 type Color bool
 
@@ -169,6 +173,26 @@ func NewWith[K comparable, V any](cmp cmp.Comparator[K]) *Tree[K, V] {
 	return &Tree[K, V]{cmp: cmp}
 }
 
+// NewFromSorted builds a perfectly balanced red-black tree from pre-sorted keys
+// and their corresponding values, using the built-in comparator.
+//
+// The middle element of each range becomes the subtree root, so every level but
+// the deepest is full. Nodes on the deepest level are colored red and all others
+// black, which satisfies the red-black properties without any fixups.
+// Keys must be sorted in ascending order and unique; behavior is undefined
+// otherwise. Panics if keys and values have different lengths.
+// Time complexity: O(n).
+func NewFromSorted[K cmp.Ordered, V any](keys []K, values []V) *Tree[K, V] {
+	if len(keys) != len(values) {
+		panic("rbtree: keys and values must have the same length")
+	}
+
+	t := New[K, V]()
+	t.fromSorted(keys, values)
+
+	return t
+}
+
 // Put inserts or updates a key-value pair in the tree.
 //
 // If the key exists, its value is updated; otherwise, a new node is inserted.
@@ -225,7 +249,8 @@ func (t *Tree[K, V]) Put(key K, val V) {
 
 // Delete deletes the node with the given key from the tree.
 //
-// Does nothing if key not found. Panics if key type is incompatible with comparator.
+// Returns the removed value and true if the key was found, or the zero value
+// and false otherwise. Panics if key type is incompatible with comparator.
 // Time complexity: O(log n).
 func (t *Tree[K, V]) Delete(key K) (value V, found bool) {
 	// Step 1: Find node to remove.
@@ -234,6 +259,8 @@ func (t *Tree[K, V]) Delete(key K) (value V, found bool) {
 		return value, false // Not found.
 	}
 
+	value = n.value // Capture before a predecessor's data may overwrite it.
+
 	// unlink: node to be unlinked.
 	// child: node that replaces unlink.
 	var child *Node[K, V]
@@ -264,16 +291,54 @@ func (t *Tree[K, V]) Delete(key K) (value V, found bool) {
 	// Step 5: Replace unlink with child in the tree.
 	t.replaceNode(unlink, child)
 
-	// Step 6: Ensure new root (if any) is black.
-	// deleteFixup usually handles this, but covers red node deletion case.
-	if unlink.parent == nil && child != nil {
+	// Step 6: A replacing child is always black afterwards. If unlink was black,
+	// a red child absorbs the lost black; if it was red, child is nil. This
+	// also keeps a new root black.
+	if child != nil {
 		child.color = black
 	}
 
 	// Step 7: Decrement tree size.
 	t.len--
 
-	return unlink.value, true
+	return value, true
+}
+
+// UpdateKey changes the key of the entry stored under oldKey to newKey,
+// keeping its value, and reports whether oldKey was present.
+//
+// If newKey still falls strictly between the keys of the entry's in-order
+// neighbors, the node is re-keyed in place without any restructuring.
+// Otherwise it falls back to Delete(oldKey) followed by Put(newKey, value);
+// in that case an entry already stored under newKey is overwritten, and the
+// tree shrinks by one.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) UpdateKey(oldKey, newKey K) bool {
+	n := t.lookup(oldKey)
+	if n == nil {
+		return false
+	}
+
+	prev, next := t.prev(n), t.next(n)
+	if (prev == nil || t.cmp(prev.key, newKey) < 0) && (next == nil || t.cmp(newKey, next.key) < 0) {
+		n.key = newKey
+
+		return true
+	}
+
+	value, _ := t.Delete(oldKey)
+	t.Put(newKey, value)
+
+	return true
+}
+
+// RemoveAndGet removes the node with the given key and returns its value.
+//
+// Returns the removed value and true if the key existed, or the zero value and
+// false otherwise. Equivalent to Delete.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) RemoveAndGet(key K) (V, bool) {
+	return t.Delete(key)
 }
 
 // Has checks if the given key exists in the tree.
@@ -284,6 +349,27 @@ func (t *Tree[K, V]) Has(key K) bool {
 	return t.lookup(key) != nil
 }
 
+// Depth returns the number of edges from the root to the node holding key,
+// so the root has depth 0, and true; or 0 and false if the key is absent.
+// Together with Node.Size it helps inspect how balanced the tree is.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) Depth(key K) (int, bool) {
+	depth := 0
+
+	for node := t.root; node != nil; depth++ {
+		switch cmp := t.cmp(key, node.key); {
+		case cmp == 0:
+			return depth, true
+		case cmp < 0:
+			node = node.left
+		default:
+			node = node.right
+		}
+	}
+
+	return 0, false
+}
+
 // Get retrieves the value associated with the given key.
 //
 // Returns the value and true if found, zero value and false otherwise.
@@ -351,6 +437,19 @@ func (t *Tree[K, V]) End() (key K, value V, found bool) {
 	return zeroKey, zeroValue, false
 }
 
+// Bounds returns the minimum and maximum entries of the tree in one call,
+// i.e. the results of Begin and End. ok is false if the tree is empty.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) Bounds() (minKey K, minValue V, maxKey K, maxValue V, ok bool) {
+	if t.root == nil {
+		return minKey, minValue, maxKey, maxValue, false
+	}
+
+	lo, hi := t.getLeftNode(t.root), t.getRightNode(t.root)
+
+	return lo.key, lo.value, hi.key, hi.value, true
+}
+
 // DeleteBegin removes the minimum key-value pair from the tree.
 // Returns the removed key, value, and true if an element was removed, otherwise false.
 // Time complexity: O(log n).
@@ -389,6 +488,22 @@ func (t *Tree[K, V]) DeleteEnd() (key K, value V, removed bool) {
 	return zeroKey, zeroValue, false
 }
 
+// PopMin removes and returns the minimum key-value pair.
+// Returns the removed key, value, and true, or zero values and false if the
+// tree is empty. Alias of DeleteBegin, named consistently across the tree packages.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) PopMin() (key K, value V, found bool) {
+	return t.DeleteBegin()
+}
+
+// PopMax removes and returns the maximum key-value pair.
+// Returns the removed key, value, and true, or zero values and false if the
+// tree is empty. Alias of DeleteEnd, named consistently across the tree packages.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) PopMax() (key K, value V, found bool) {
+	return t.DeleteEnd()
+}
+
 // Floor finds the largest node less than or equal to the given key.
 //
 // Returns the node and true if found, nil and false otherwise. Panics if the
@@ -502,6 +617,31 @@ func (t *Tree[K, V]) Clone() container.Map[K, V] {
 	return newTree
 }
 
+// SubMap returns a new tree, with the same comparator, holding copies of the
+// entries whose keys lie in [low, high). It is a snapshot rather than a live
+// view: later changes to either tree do not affect the other.
+//
+// The range is located with Ceiling and copied in order, and the new tree is
+// built balanced in one pass, as by NewFromSorted. If low >= high the result
+// is empty.
+// Time complexity: O(log n + k) for k copied entries.
+func (t *Tree[K, V]) SubMap(low, high K) *Tree[K, V] {
+	var (
+		keys   []K
+		values []V
+	)
+
+	for node, _ := t.Ceiling(low); node != nil && t.cmp(node.key, high) < 0; node = t.next(node) {
+		keys = append(keys, node.key)
+		values = append(values, node.value)
+	}
+
+	sub := NewWith[K, V](t.cmp)
+	sub.fromSorted(keys, values)
+
+	return sub
+}
+
 // Len returns the number of nodes in the tree.
 //
 // Time complexity: O(1).
@@ -587,27 +727,143 @@ func (t *Tree[K, V]) Comparator() cmp.Comparator[K] {
 	return t.cmp
 }
 
+// Reorder replaces the tree's comparator and rebuilds the tree under the new order.
+//
+// All entries are collected, the tree is cleared, and every entry is re-inserted
+// using c. Keys that compare equal under c collapse into one entry, with the value
+// of the key that came last in the previous order winning. Panics if c is nil.
+// Time complexity: O(n log n).
+func (t *Tree[K, V]) Reorder(c cmp.Comparator[K]) {
+	if c == nil {
+		panic("rbtree: nil comparator")
+	}
+
+	keys, vals := t.Entries()
+
+	t.Clear()
+	t.cmp = c
+
+	for i, k := range keys {
+		t.Put(k, vals[i])
+	}
+}
+
+// BlackHeight returns the number of black nodes on the path from the root to
+// any nil leaf, not counting the leaf itself. In a valid tree this is the
+// same for every path; this walks the leftmost one.
+// Returns 0 if the tree is empty.
+// Time complexity: O(log n).
+func (t *Tree[K, V]) BlackHeight() int {
+	height := 0
+
+	for n := t.root; n != nil; n = n.left {
+		if n.color == black {
+			height++
+		}
+	}
+
+	return height
+}
+
+// VerifyProperties checks the red-black tree invariants:
+//   - the root is black,
+//   - red nodes have only black children,
+//   - every root-to-nil path has the same number of black nodes,
+//   - keys are in strictly increasing in-order sequence under the comparator,
+//   - each child's parent link points back to its parent.
+//
+// Returns nil if all hold, or an error wrapping ErrInvalidTree that names the
+// first violating node. Intended for tests and debugging.
+// Time complexity: O(n).
+func (t *Tree[K, V]) VerifyProperties() error {
+	if t.root == nil {
+		return nil
+	}
+
+	if t.root.parent != nil {
+		return fmt.Errorf("%w: root %v has a parent", ErrInvalidTree, t.root.key)
+	}
+
+	if t.root.color != black {
+		return fmt.Errorf("%w: root %v is red", ErrInvalidTree, t.root.key)
+	}
+
+	_, err := t.verify(t.root, nil, nil)
+
+	return err
+}
+
+// verify checks the subtree rooted at n, whose keys must lie strictly between
+// lo and hi when those are non-nil, and returns its black height.
+func (t *Tree[K, V]) verify(n, lo, hi *Node[K, V]) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+
+	if lo != nil && t.cmp(n.key, lo.key) <= 0 {
+		return 0, fmt.Errorf("%w: node %v is not greater than %v", ErrInvalidTree, n.key, lo.key)
+	}
+
+	if hi != nil && t.cmp(n.key, hi.key) >= 0 {
+		return 0, fmt.Errorf("%w: node %v is not less than %v", ErrInvalidTree, n.key, hi.key)
+	}
+
+	for _, c := range []*Node[K, V]{n.left, n.right} {
+		if c == nil {
+			continue
+		}
+
+		if c.parent != n {
+			return 0, fmt.Errorf("%w: node %v has a wrong parent link", ErrInvalidTree, c.key)
+		}
+
+		if n.color == red && c.color == red {
+			return 0, fmt.Errorf("%w: red node %v has red child %v", ErrInvalidTree, n.key, c.key)
+		}
+	}
+
+	lh, err := t.verify(n.left, lo, n)
+	if err != nil {
+		return 0, err
+	}
+
+	rh, err := t.verify(n.right, n, hi)
+	if err != nil {
+		return 0, err
+	}
+
+	if lh != rh {
+		return 0, fmt.Errorf("%w: node %v has unequal black heights %d and %d", ErrInvalidTree, n.key, lh, rh)
+	}
+
+	return lh + ternary(n.color == black, 1, 0), nil
+}
+
 // Iter returns an iterator over all key-value pairs in sorted order.
 // Yields pairs in in-order traversal.
 //
 // Time complexity: O(log n) per element.
 func (t *Tree[K, V]) Iter() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		node := t.GetBeginNode()
-		for node != nil {
-			if !yield(node.Key(), node.Value()) {
+		for node := t.GetBeginNode(); node != nil; node = t.next(node) {
+			if !yield(node.key, node.value) {
 				return
 			}
+		}
+	}
+}
 
-			if node.Right() != nil {
-				node = t.getLeftNode(node.Right())
-			} else {
-				for node.Parent() != nil && node == node.Parent().Right() {
-					node = node.Parent()
-				}
-
-				node = node.Parent()
-			}
+// WalkNodes calls f for each node in sorted (in-order) order, stopping early
+// if f returns false. Unlike Iter it exposes the nodes themselves, so callers
+// can inspect their structure or key external data by node identity.
+// The walk is iterative, following parent pointers, so it uses no recursion.
+// f must not modify the tree.
+//
+// Time complexity: O(n).
+func (t *Tree[K, V]) WalkNodes(f func(n *Node[K, V]) bool) {
+	for node := t.GetBeginNode(); node != nil; node = t.next(node) {
+		if !f(node) {
+			return
 		}
 	}
 }
@@ -618,23 +874,146 @@ func (t *Tree[K, V]) Iter() iter.Seq2[K, V] {
 // Time complexity: O(log n) per element.
 func (t *Tree[K, V]) RIter() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		node := t.GetEndNode()
-		for node != nil {
-			if !yield(node.Key(), node.Value()) {
+		for node := t.GetEndNode(); node != nil; node = t.prev(node) {
+			if !yield(node.key, node.value) {
 				return
 			}
+		}
+	}
+}
 
-			if node.Left() != nil {
-				node = t.getRightNode(node.Left())
-			} else {
-				for node.Parent() != nil && node == node.Parent().Left() {
-					node = node.Parent()
-				}
+var _ container.ReverseIteratorWithKey[string, int] = (*Iterator[string, int])(nil)
 
-				node = node.Parent()
-			}
-		}
+// position records where an Iterator sits relative to the tree's elements.
+type position byte
+
+const (
+	begin   position = iota // Before the first element.
+	between                 // On an element.
+	end                     // Past the last element.
+)
+
+// Iterator is a stateful, bidirectional iterator over the tree's key-value
+// pairs in sorted order, satisfying container.ReverseIteratorWithKey.
+//
+// Iter corresponds to Iterator followed by repeated Next calls, and RIter to
+// ReverseIterator followed by repeated Prev calls. Unlike those closures, an
+// Iterator can be paused, moved in either direction and repositioned. It is
+// invalidated by any modification of the tree other than through its own
+// methods.
+type Iterator[K comparable, V any] struct {
+	tree     *Tree[K, V]
+	node     *Node[K, V]
+	position position
+}
+
+// Iterator returns a stateful iterator positioned before the first element.
+// Time complexity: O(1).
+func (t *Tree[K, V]) Iterator() *Iterator[K, V] {
+	return &Iterator[K, V]{tree: t, position: begin}
+}
+
+// ReverseIterator returns a stateful iterator positioned past the last element,
+// ready for backward traversal with Prev.
+// Time complexity: O(1).
+func (t *Tree[K, V]) ReverseIterator() *Iterator[K, V] {
+	return &Iterator[K, V]{tree: t, position: end}
+}
+
+// Next moves the iterator to the next element and returns true if there was one.
+// Time complexity: O(log n) worst case, amortized O(1).
+func (it *Iterator[K, V]) Next() bool {
+	switch it.position {
+	case end:
+		return false
+	case begin:
+		it.node = it.tree.GetBeginNode()
+	default:
+		it.node = it.tree.next(it.node)
+	}
+
+	if it.node == nil {
+		it.position = end
+
+		return false
 	}
+
+	it.position = between
+
+	return true
+}
+
+// Prev moves the iterator to the previous element and returns true if there was one.
+// Time complexity: O(log n) worst case, amortized O(1).
+func (it *Iterator[K, V]) Prev() bool {
+	switch it.position {
+	case begin:
+		return false
+	case end:
+		it.node = it.tree.GetEndNode()
+	default:
+		it.node = it.tree.prev(it.node)
+	}
+
+	if it.node == nil {
+		it.position = begin
+
+		return false
+	}
+
+	it.position = between
+
+	return true
+}
+
+// Key returns the key of the current element.
+// Only valid after Next, Prev, First or Last returned true.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Key() K {
+	return it.node.key
+}
+
+// Value returns the value of the current element.
+// Only valid after Next, Prev, First or Last returned true.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Value() V {
+	return it.node.value
+}
+
+// Node returns the current node, or nil if the iterator is not on an element.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Node() *Node[K, V] {
+	return it.node
+}
+
+// Begin resets the iterator to before the first element.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) Begin() {
+	it.node = nil
+	it.position = begin
+}
+
+// End moves the iterator past the last element.
+// Time complexity: O(1).
+func (it *Iterator[K, V]) End() {
+	it.node = nil
+	it.position = end
+}
+
+// First moves the iterator to the first element and returns true if there was one.
+// Time complexity: O(log n).
+func (it *Iterator[K, V]) First() bool {
+	it.Begin()
+
+	return it.Next()
+}
+
+// Last moves the iterator to the last element and returns true if there was one.
+// Time complexity: O(log n).
+func (it *Iterator[K, V]) Last() bool {
+	it.End()
+
+	return it.Prev()
 }
 
 // lookup finds the node with the given key.
@@ -678,6 +1057,32 @@ func (t *Tree[K, V]) getRightNode(node *Node[K, V]) *Node[K, V] {
 	return node
 }
 
+// next returns the in-order successor of node, or nil if node is the last.
+func (t *Tree[K, V]) next(node *Node[K, V]) *Node[K, V] {
+	if node.right != nil {
+		return t.getLeftNode(node.right)
+	}
+
+	for node.parent != nil && node == node.parent.right {
+		node = node.parent
+	}
+
+	return node.parent
+}
+
+// prev returns the in-order predecessor of node, or nil if node is the first.
+func (t *Tree[K, V]) prev(node *Node[K, V]) *Node[K, V] {
+	if node.left != nil {
+		return t.getRightNode(node.left)
+	}
+
+	for node.parent != nil && node == node.parent.left {
+		node = node.parent
+	}
+
+	return node.parent
+}
+
 // replaceNode substitutes the `old` node with the `new` node in the tree structure.
 // It updates the parent of `old` to point to `new`, and sets `new`'s parent
 // to be `old`'s parent. This function does not modify children of `old` or `new`.
@@ -1155,6 +1560,45 @@ func ternary[T any](cond bool, trueVal, falseVal T) T {
 	return falseVal
 }
 
+// fromSorted replaces the contents of the empty tree t with a balanced tree
+// built from sorted, unique keys and their values.
+func (t *Tree[K, V]) fromSorted(keys []K, values []V) {
+	if len(keys) == 0 {
+		return
+	}
+
+	// Depth of the deepest level of a size-balanced tree with n nodes.
+	maxDepth := 0
+	for n := len(keys); n > 1; n >>= 1 {
+		maxDepth++
+	}
+
+	t.root = buildSorted(keys, values, nil, 0, maxDepth)
+	t.root.color = black
+	t.len = len(keys)
+}
+
+// buildSorted recursively builds a balanced subtree from sorted keys and values.
+// Nodes at maxDepth are colored red, all others black.
+func buildSorted[K comparable, V any](keys []K, values []V, parent *Node[K, V], depth, maxDepth int) *Node[K, V] {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	mid := len(keys) / 2
+	n := &Node[K, V]{
+		key:    keys[mid],
+		value:  values[mid],
+		color:  ternary(depth == maxDepth, red, black),
+		parent: parent,
+	}
+
+	n.left = buildSorted(keys[:mid], values[:mid], n, depth+1, maxDepth)
+	n.right = buildSorted(keys[mid+1:], values[mid+1:], n, depth+1, maxDepth)
+
+	return n
+}
+
 // cloneNode creates a deep copy of a node and its subtree.
 // node is the node to be copied.
 // parent is the parent for the new node in the cloned tree.
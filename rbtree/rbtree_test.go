@@ -2,11 +2,13 @@ package rbtree_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
 	"testing"
 
+	"github.com/qntx/gods/container"
 	"github.com/qntx/gods/rbtree"
 )
 
@@ -356,3 +358,519 @@ func TestRedBlackTreeString(t *testing.T) {
 		t.Errorf("String should start with container name")
 	}
 }
+
+// assertRedBlack checks the red-black invariants, parent links and BST order of
+// the tree using only the exported node accessors. The root is required to be
+// black, so its color is used as the reference for black nodes.
+func assertRedBlack[K comparable, V any](t *testing.T, tree *rbtree.Tree[K, V]) {
+	t.Helper()
+
+	root := tree.GetBeginNode()
+	if root == nil {
+		return
+	}
+
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+
+	blackColor := root.Color()
+	cmp := tree.Comparator()
+
+	var walk func(n *rbtree.Node[K, V]) int
+
+	walk = func(n *rbtree.Node[K, V]) int {
+		if n == nil {
+			return 1
+		}
+
+		for _, c := range []*rbtree.Node[K, V]{n.Left(), n.Right()} {
+			if c == nil {
+				continue
+			}
+
+			if c.Parent() != n {
+				t.Fatalf("Node %v has wrong parent", c)
+			}
+
+			if n.Color() != blackColor && c.Color() != blackColor {
+				t.Fatalf("Red node %v has red child %v", n, c)
+			}
+		}
+
+		if l := n.Left(); l != nil && cmp(l.Key(), n.Key()) >= 0 {
+			t.Fatalf("Left child %v not less than %v", l, n)
+		}
+
+		if r := n.Right(); r != nil && cmp(r.Key(), n.Key()) <= 0 {
+			t.Fatalf("Right child %v not greater than %v", r, n)
+		}
+
+		lh, rh := walk(n.Left()), walk(n.Right())
+		if lh != rh {
+			t.Fatalf("Unequal black heights at %v: %d vs %d", n, lh, rh)
+		}
+
+		if n.Color() == blackColor {
+			lh++
+		}
+
+		return lh
+	}
+
+	if actualValue, expectedValue := walk(root)-1, tree.BlackHeight(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v for black height", actualValue, expectedValue)
+	}
+
+	if err := tree.VerifyProperties(); err != nil {
+		t.Fatalf("VerifyProperties: %v", err)
+	}
+
+	if actualValue, expectedValue := root.Size(), tree.Len(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v for tree size", actualValue, expectedValue)
+	}
+}
+
+func TestRedBlackTreeNewFromSorted(t *testing.T) {
+	t.Parallel()
+
+	empty := rbtree.NewFromSorted[int, int](nil, nil)
+	if actualValue := empty.Len(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	for n := 1; n <= 130; n++ {
+		keys := make([]int, n)
+		values := make([]string, n)
+
+		for i := range n {
+			keys[i] = i * 2
+			values[i] = fmt.Sprint(i)
+		}
+
+		tree := rbtree.NewFromSorted(keys, values)
+		assertRedBlack(t, tree)
+
+		if actualValue := tree.Keys(); !slices.Equal(actualValue, keys) {
+			t.Errorf("Got %v expected %v", actualValue, keys)
+		}
+
+		if actualValue := tree.Values(); !slices.Equal(actualValue, values) {
+			t.Errorf("Got %v expected %v", actualValue, values)
+		}
+
+		tree.Put(-1, "x")
+		tree.Put(n*2, "y")
+		assertRedBlack(t, tree)
+	}
+}
+
+func TestRedBlackTreeReorder(t *testing.T) {
+	t.Parallel()
+
+	tree := rbtree.New[int, string]()
+	for i := range 20 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	tree.Reorder(func(a, b int) int { return b - a })
+	assertRedBlack(t, tree)
+
+	if actualValue := tree.Len(); actualValue != 20 {
+		t.Errorf("Got %v expected %v", actualValue, 20)
+	}
+
+	if k, v, _ := tree.Begin(); k != 19 || v != "19" {
+		t.Errorf("Got %v,%v expected %v,%v", k, v, 19, "19")
+	}
+
+	keys := tree.Keys()
+	if !slices.IsSortedFunc(keys, func(a, b int) int { return b - a }) {
+		t.Errorf("Keys %v are not in descending order", keys)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Reorder should panic on nil comparator")
+		}
+	}()
+
+	tree.Reorder(nil)
+}
+
+func TestRedBlackTreeRemoveAndGet(t *testing.T) {
+	t.Parallel()
+
+	tree := rbtree.New[int, string]()
+
+	if value, found := tree.RemoveAndGet(1); value != "" || found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "", false)
+	}
+
+	for i := range 200 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	// Remove in a scattered order so that nodes with two children, nodes with a
+	// single red child and leaves are all exercised.
+	for i := range 200 {
+		key := i * 37 % 200
+
+		value, found := tree.RemoveAndGet(key)
+		if expected := fmt.Sprint(key); value != expected || !found {
+			t.Errorf("RemoveAndGet(%v): got %v,%v expected %v,%v", key, value, found, expected, true)
+		}
+
+		if tree.Has(key) {
+			t.Errorf("Key %v still present after removal", key)
+		}
+
+		if actualValue, expectedValue := tree.Len(), 199-i; actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+
+		assertRedBlack(t, tree)
+	}
+
+	if value, found := tree.RemoveAndGet(0); value != "" || found {
+		t.Errorf("Got %v,%v expected %v,%v", value, found, "", false)
+	}
+}
+
+func TestRedBlackTreeIterator(t *testing.T) {
+	t.Parallel()
+
+	var it container.ReverseIteratorWithKey[int, string] = rbtree.New[int, string]().Iterator()
+	if it.Next() || it.Prev() || it.First() || it.Last() {
+		t.Errorf("Iterator on empty tree should not move")
+	}
+
+	tree := rbtree.New[int, string]()
+	for _, k := range []int{5, 3, 8, 1, 4, 7, 9, 2, 6} {
+		tree.Put(k, fmt.Sprint(k))
+	}
+
+	it = tree.Iterator()
+
+	var keys []int
+	for it.Next() {
+		if actualValue, expectedValue := it.Value(), fmt.Sprint(it.Key()); actualValue != expectedValue {
+			t.Errorf("Got %v expected %v", actualValue, expectedValue)
+		}
+
+		keys = append(keys, it.Key())
+	}
+
+	if actualValue, expectedValue := keys, tree.Keys(); !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// Walking back from past the end visits everything in reverse.
+	keys = keys[:0]
+	for it.Prev() {
+		keys = append(keys, it.Key())
+	}
+
+	var reversed []int
+	for k := range tree.RIter() {
+		reversed = append(reversed, k)
+	}
+
+	if !slices.Equal(keys, reversed) {
+		t.Errorf("Got %v expected %v", keys, reversed)
+	}
+
+	rit := tree.ReverseIterator()
+	if !rit.Prev() || rit.Key() != 9 {
+		t.Errorf("Got %v expected %v", rit.Key(), 9)
+	}
+
+	if !rit.Prev() || !rit.Next() || rit.Key() != 9 || rit.Next() {
+		t.Errorf("Iterator should change direction in place")
+	}
+
+	if !rit.First() || rit.Key() != 1 || !rit.Last() || rit.Key() != 9 {
+		t.Errorf("First/Last should reposition the iterator")
+	}
+
+	rit.Begin()
+
+	if rit.Prev() || !rit.Next() || rit.Key() != 1 {
+		t.Errorf("Begin should position the iterator before the first element")
+	}
+}
+
+func TestRedBlackTreeVerifyProperties(t *testing.T) {
+	t.Parallel()
+
+	tree := rbtree.New[int, int]()
+
+	if err := tree.VerifyProperties(); err != nil {
+		t.Errorf("Empty tree: got %v expected nil", err)
+	}
+
+	if actualValue := tree.BlackHeight(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	for i := range 100 {
+		tree.Put(i, i)
+
+		if err := tree.VerifyProperties(); err != nil {
+			t.Fatalf("After Put(%v): %v", i, err)
+		}
+	}
+
+	// A red-black tree of n nodes has black height at least log2(n+1)/2.
+	if actualValue := tree.BlackHeight(); actualValue < 4 {
+		t.Errorf("Got %v expected at least %v", actualValue, 4)
+	}
+
+	// NewFromSorted trusts its input, so unsorted keys produce a tree whose
+	// shape and colors are valid but whose key order is not.
+	broken := rbtree.NewFromSorted([]int{3, 1, 2}, []int{0, 0, 0})
+
+	err := broken.VerifyProperties()
+	if !errors.Is(err, rbtree.ErrInvalidTree) {
+		t.Fatalf("Got %v expected %v", err, rbtree.ErrInvalidTree)
+	}
+
+	if !strings.Contains(err.Error(), "node 3") {
+		t.Errorf("Error %q should name the violating node", err)
+	}
+}
+
+func TestRedBlackTreeWalkNodes(t *testing.T) {
+	t.Parallel()
+
+	tree := rbtree.New[int, string]()
+	tree.WalkNodes(func(*rbtree.Node[int, string]) bool {
+		t.Errorf("WalkNodes should not visit nodes of an empty tree")
+
+		return true
+	})
+
+	for i := range 20 {
+		tree.Put(i*7%20, fmt.Sprint(i*7%20))
+	}
+
+	var keys []int
+
+	parents := map[*rbtree.Node[int, string]]*rbtree.Node[int, string]{}
+
+	tree.WalkNodes(func(n *rbtree.Node[int, string]) bool {
+		keys = append(keys, n.Key())
+		parents[n] = n.Parent()
+
+		return true
+	})
+
+	if actualValue, expectedValue := keys, tree.Keys(); !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := len(parents), tree.Len(); actualValue != expectedValue {
+		t.Errorf("Got %v expected %v distinct nodes", actualValue, expectedValue)
+	}
+
+	for n := range parents {
+		if n != tree.GetNode(n.Key()) {
+			t.Errorf("Node %v is not the tree's node for its key", n)
+		}
+	}
+
+	count := 0
+
+	tree.WalkNodes(func(n *rbtree.Node[int, string]) bool {
+		count++
+
+		return n.Key() < 4
+	})
+
+	if count != 5 {
+		t.Errorf("Got %v expected %v", count, 5)
+	}
+}
+
+func TestRedBlackTreePopMinMax(t *testing.T) {
+	t.Parallel()
+
+	tree := rbtree.New[int, string]()
+
+	if k, v, found := tree.PopMin(); k != 0 || v != "" || found {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", k, v, found, 0, "", false)
+	}
+
+	if k, v, found := tree.PopMax(); k != 0 || v != "" || found {
+		t.Errorf("Got %v,%v,%v expected %v,%v,%v", k, v, found, 0, "", false)
+	}
+
+	for i := range 50 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	for i := range 25 {
+		if k, v, found := tree.PopMin(); k != i || v != fmt.Sprint(i) || !found {
+			t.Errorf("PopMin: got %v,%v,%v expected %v,%v,%v", k, v, found, i, fmt.Sprint(i), true)
+		}
+
+		if k, v, found := tree.PopMax(); k != 49-i || v != fmt.Sprint(49-i) || !found {
+			t.Errorf("PopMax: got %v,%v,%v expected %v,%v,%v", k, v, found, 49-i, fmt.Sprint(49-i), true)
+		}
+
+		assertRedBlack(t, tree)
+	}
+
+	if !tree.IsEmpty() {
+		t.Errorf("Got %v expected %v", tree.Len(), 0)
+	}
+}
+
+func TestRedBlackTreeBounds(t *testing.T) {
+	tree := rbtree.New[int, string]()
+
+	if _, _, _, _, ok := tree.Bounds(); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+
+	tree.Put(5, "e")
+
+	if minK, minV, maxK, maxV, ok := tree.Bounds(); minK != 5 || minV != "e" || maxK != 5 || maxV != "e" || !ok {
+		t.Errorf("Got %v,%v,%v,%v,%v expected %v,%v,%v,%v,%v", minK, minV, maxK, maxV, ok, 5, "e", 5, "e", true)
+	}
+
+	tree.Put(1, "a")
+	tree.Put(9, "i")
+	tree.Put(3, "c")
+
+	if minK, minV, maxK, maxV, ok := tree.Bounds(); minK != 1 || minV != "a" || maxK != 9 || maxV != "i" || !ok {
+		t.Errorf("Got %v,%v,%v,%v,%v expected %v,%v,%v,%v,%v", minK, minV, maxK, maxV, ok, 1, "a", 9, "i", true)
+	}
+}
+
+func TestRedBlackTreeUpdateKey(t *testing.T) {
+	tree := rbtree.New[int, string]()
+	for _, k := range []int{10, 20, 30, 40, 50} {
+		tree.Put(k, fmt.Sprint(k))
+	}
+
+	if tree.UpdateKey(99, 100) {
+		t.Errorf("UpdateKey should report false for a missing key")
+	}
+
+	// In place: 30 -> 35 stays between 20 and 40.
+	node := tree.GetNode(30)
+
+	if !tree.UpdateKey(30, 35) || tree.GetNode(35) != node {
+		t.Errorf("UpdateKey should re-key the node in place")
+	}
+
+	// Fallback: 10 -> 45 moves past its neighbors.
+	if !tree.UpdateKey(10, 45) {
+		t.Errorf("UpdateKey should report true for a present key")
+	}
+
+	if actualValue, expectedValue := tree.Keys(), []int{20, 35, 40, 45, 50}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := tree.Values(), []string{"20", "30", "40", "10", "50"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	assertRedBlack(t, tree)
+
+	// Moving onto an existing key overwrites it.
+	if !tree.UpdateKey(20, 50) || tree.Len() != 4 {
+		t.Errorf("Got len %v expected %v", tree.Len(), 4)
+	}
+
+	if v, _ := tree.Get(50); v != "20" {
+		t.Errorf("Got %v expected %v", v, "20")
+	}
+
+	assertRedBlack(t, tree)
+}
+
+func TestRedBlackTreeDepth(t *testing.T) {
+	tree := rbtree.New[int, int]()
+
+	if depth, found := tree.Depth(1); depth != 0 || found {
+		t.Errorf("Got %v,%v expected %v,%v", depth, found, 0, false)
+	}
+
+	const n = 127
+	for i := range n {
+		tree.Put(i, i)
+	}
+
+	maxDepth := 0
+
+	for i := range n {
+		depth, found := tree.Depth(i)
+		if !found {
+			t.Fatalf("Got %v expected %v for key %v", found, true, i)
+		}
+
+		// Depth counts the edges up to the root.
+		expected := 0
+		for node := tree.GetNode(i); node.Parent() != nil; node = node.Parent() {
+			expected++
+		}
+
+		if depth != expected {
+			t.Errorf("Got %v expected %v for key %v", depth, expected, i)
+		}
+
+		maxDepth = max(maxDepth, depth)
+	}
+
+	// A red-black tree of n nodes is at most 2*log2(n+1) high.
+	if maxDepth >= 2*7 {
+		t.Errorf("Got max depth %v expected less than %v", maxDepth, 2*7)
+	}
+
+	if depth, found := tree.Depth(n); depth != 0 || found {
+		t.Errorf("Got %v,%v expected %v,%v", depth, found, 0, false)
+	}
+}
+
+func TestRedBlackTreeSubMap(t *testing.T) {
+	tree := rbtree.New[int, string]()
+	for i := range 50 {
+		tree.Put(i*2, fmt.Sprint(i*2))
+	}
+
+	sub := tree.SubMap(9, 31)
+	if actualValue, expectedValue := sub.Keys(), []int{10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if err := sub.VerifyProperties(); err != nil {
+		t.Errorf("SubMap produced an invalid tree: %v", err)
+	}
+
+	// The high bound is exclusive, the low bound inclusive.
+	if actualValue, expectedValue := tree.SubMap(10, 14).Keys(), []int{10, 12}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	// The result is a copy, independent of the original.
+	sub.Put(11, "11")
+	sub.Delete(10)
+	tree.Put(12, "twelve")
+
+	if v, _ := sub.Get(12); v != "12" || !tree.Has(10) || tree.Has(11) {
+		t.Errorf("SubMap should not share state with the original")
+	}
+
+	for _, bounds := range [][2]int{{40, 40}, {60, 20}, {100, 200}, {-10, 0}} {
+		if sub := tree.SubMap(bounds[0], bounds[1]); !sub.IsEmpty() {
+			t.Errorf("Got %v expected an empty tree for %v", sub.Keys(), bounds)
+		}
+	}
+
+	if actualValue := tree.SubMap(-10, 1000).Len(); actualValue != 50 {
+		t.Errorf("Got %v expected %v", actualValue, 50)
+	}
+}
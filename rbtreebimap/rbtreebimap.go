@@ -169,6 +169,22 @@ func (m *Map[K, V]) Clone() container.Map[K, V] {
 	}
 }
 
+// Merge inserts every pair of other into m, in other's key order.
+//
+// Each pair is applied as by Put, so the bijection is preserved: if the key is
+// already mapped to a different value, or the value is already mapped from a
+// different key, those existing pairs are removed from both sides and the pair
+// from other wins. Merging a map into itself does nothing.
+func (m *Map[K, V]) Merge(other *Map[K, V]) {
+	if other == m {
+		return
+	}
+
+	for k, v := range other.fwd.Iter() {
+		m.Put(k, v)
+	}
+}
+
 func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
 	return m.fwd.MarshalJSON()
 }
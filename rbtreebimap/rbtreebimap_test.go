@@ -191,3 +191,44 @@ func TestMapString(t *testing.T) {
 		t.Errorf("String should start with container name")
 	}
 }
+
+func TestMapCloneAndMerge(t *testing.T) {
+	m := rbtreebimap.New[int, string]()
+	m.Put(1, "a")
+	m.Put(2, "b")
+	m.Put(3, "c")
+
+	clone := m.Clone()
+	clone.Put(4, "d")
+	clone.Delete(1)
+
+	if actualValue, expectedValue := m.Keys(), []int{1, 2, 3}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Clone should not share state, got %v expected %v", actualValue, expectedValue)
+	}
+
+	other := rbtreebimap.New[int, string]()
+	other.Put(2, "x") // key collision: 2 now maps to "x", "b" is dropped.
+	other.Put(9, "c") // value collision: "c" now maps from 9, key 3 is dropped.
+	other.Put(5, "e")
+
+	m.Merge(other)
+	m.Merge(m)
+
+	if actualValue, expectedValue := m.Keys(), []int{1, 2, 5, 9}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := m.Values(), []string{"a", "c", "e", "x"}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	for k, v := range m.Iter() {
+		if actualValue, ok := m.GetKey(v); !ok || actualValue != k {
+			t.Errorf("GetKey(%v): got %v,%v expected %v,%v", v, actualValue, ok, k, true)
+		}
+	}
+
+	if m.HasValue("b") || m.Has(3) {
+		t.Errorf("Evicted pairs should be gone from both sides")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"strings"
 
 	"github.com/qntx/gods/container"
@@ -34,6 +35,7 @@ var (
 )
 
 var _ container.Deque[int] = (*Deque[int])(nil)
+var _ container.Queue[int] = (*Deque[int])(nil)
 var _ json.Marshaler = (*Deque[int])(nil)
 var _ json.Unmarshaler = (*Deque[int])(nil)
 
@@ -101,6 +103,25 @@ func NewFrom[T comparable](values []T, capacity int, growable bool) *Deque[T] {
 	return d
 }
 
+// Collect creates a new Deque holding the elements yielded by seq, in order.
+//
+// It is the iterator counterpart of NewFrom: the capacity parameter specifies
+// the initial capacity, a growable deque expands to hold every element, and an
+// overwrite-mode deque keeps only the last capacity elements.
+//
+// Example:
+//
+//	d := deque.Collect(slices.Values([]int{1, 2, 3}), 2, false) // [2,3]
+func Collect[T comparable](seq iter.Seq[T], capacity int, growable bool) *Deque[T] {
+	d := NewWith[T](capacity, growable)
+
+	for v := range seq {
+		d.PushBack(v)
+	}
+
+	return d
+}
+
 // PushFront inserts an element at the front of the deque.
 //
 // In overwrite mode (growable=false), overwrites the oldest element (back) if full.
@@ -147,6 +168,101 @@ func (d *Deque[T]) PushBack(val T) {
 	}
 }
 
+// TryPushFront inserts an element at the front of the deque unless doing so
+// would overwrite an existing element.
+//
+// Returns false and leaves the deque unchanged if it is full in overwrite mode.
+// In expansion mode it always succeeds, growing the buffer if needed.
+//
+// Time complexity: O(1) amortized.
+func (d *Deque[T]) TryPushFront(val T) bool {
+	if d.Full() && !d.growable {
+		return false
+	}
+
+	d.PushFront(val)
+
+	return true
+}
+
+// TryPushBack inserts an element at the back of the deque unless doing so
+// would overwrite an existing element.
+//
+// Returns false and leaves the deque unchanged if it is full in overwrite mode.
+// In expansion mode it always succeeds, growing the buffer if needed.
+//
+// Time complexity: O(1) amortized.
+func (d *Deque[T]) TryPushBack(val T) bool {
+	if d.Full() && !d.growable {
+		return false
+	}
+
+	d.PushBack(val)
+
+	return true
+}
+
+// PushBackSlice appends vals to the back of the deque, in order, so that the
+// last element of vals becomes the back.
+//
+// In expansion mode the buffer grows at most once to fit all of vals. In
+// overwrite mode the result is the same as pushing the elements one by one:
+// only the last Capacity() elements of the combined sequence are kept.
+//
+// Time complexity: O(k) where k is len(vals), plus O(n) if the deque grows.
+func (d *Deque[T]) PushBackSlice(vals []T) {
+	d.pushBackN(len(vals), func(i int) T { return vals[i] })
+}
+
+// PushFrontSlice prepends vals to the front of the deque, preserving their
+// order, so that vals[0] becomes the front. This differs from calling
+// PushFront for each element, which would reverse them.
+//
+// In expansion mode the buffer grows at most once to fit all of vals. In
+// overwrite mode elements are dropped from the back: only the first
+// Capacity() elements of the combined sequence are kept.
+//
+// Time complexity: O(k) where k is len(vals), plus O(n) if the deque grows.
+func (d *Deque[T]) PushFrontSlice(vals []T) {
+	d.pushFrontN(len(vals), func(i int) T { return vals[i] })
+}
+
+// Concat appends the elements of other to the back of the deque, from front
+// to back, like PushBackSlice(other.Values()) but without the intermediate
+// slice. other is not modified, and may be the deque itself.
+//
+// In expansion mode the buffer grows at most once. In overwrite mode only the
+// last Capacity() elements of the combined sequence are kept.
+//
+// Time complexity: O(k) where k is other.Len(), plus O(n) if the deque grows.
+func (d *Deque[T]) Concat(other *Deque[T]) {
+	if other == d {
+		d.PushBackSlice(other.Values())
+
+		return
+	}
+
+	d.pushBackN(other.len, other.at)
+}
+
+// ConcatFront prepends the elements of other to the front of the deque,
+// preserving their order, like PushFrontSlice(other.Values()) but without the
+// intermediate slice. other is not modified, and may be the deque itself.
+//
+// In expansion mode the buffer grows at most once. In overwrite mode only the
+// first Capacity() elements of the combined sequence are kept.
+//
+// Time complexity: O(k) where k is other.Len(), plus O(n) if the deque grows.
+func (d *Deque[T]) ConcatFront(other *Deque[T]) {
+	if other == d {
+		d.PushFrontSlice(other.Values())
+
+		return
+	}
+
+	d.pushFrontN(other.len, other.at)
+}
+
 // PopFront removes and returns the front element.
 //
 // Returns the zero value of T and false if the deque is empty.
@@ -181,9 +297,35 @@ func (d *Deque[T]) PopBack() (val T, ok bool) {
 	return val, true
 }
 
+// Enqueue adds an element to the back of the deque, so it can be used as a
+// container.Queue. Equivalent to PushBack.
+//
+// Time complexity: O(1) amortized.
+func (d *Deque[T]) Enqueue(val T) {
+	d.PushBack(val)
+}
+
+// Dequeue removes and returns the front element, so it can be used as a
+// container.Queue. Equivalent to PopFront.
+//
+// Time complexity: O(1).
+func (d *Deque[T]) Dequeue() (val T, ok bool) {
+	return d.PopFront()
+}
+
+// Peek returns the front element without removing it, so it can be used as a
+// container.Queue. Equivalent to Front.
+//
+// Time complexity: O(1).
+func (d *Deque[T]) Peek() (val T, ok bool) {
+	return d.Front()
+}
+
 // Insert adds an element at the specified index, shifting subsequent elements toward the back.
 // Index 0 inserts at the front, Len() inserts at the back. If the deque is full and growable,
-// the capacity is doubled. Panics if the index is invalid (out of range [0, Len()]).
+// the capacity is doubled. If it is full in overwrite mode, the front element is dropped
+// (the back element for index 0, as with PushFront) and val still ends up immediately
+// before the element that was at idx. Panics if the index is invalid (out of range [0, Len()]).
 //
 // Time complexity: O(n) where n is the number of elements after the insertion point.
 func (d *Deque[T]) Insert(idx int, val T) {
@@ -204,11 +346,19 @@ func (d *Deque[T]) Insert(idx int, val T) {
 	}
 
 	if d.Full() {
-		if d.growable {
+		switch {
+		case d.growable:
 			d.Grow(d.Capacity() * growthFactor)
-		} else {
+		case idx == 1:
+			// Dropping the front frees exactly the slot val belongs in.
+			d.buf[d.start] = val
+
+			return
+		default:
+			// Drop the front element; everything after it moves down one index.
 			d.start = d.next(d.start)
 			d.len--
+			idx--
 		}
 	}
 
@@ -280,6 +430,22 @@ func (d *Deque[T]) Remove(idx int) (val T, ok bool) {
 	return val, true
 }
 
+// RemoveValue removes the first occurrence of val, searching from front to
+// back, and reports whether it was found.
+//
+// Time complexity: O(n).
+func (d *Deque[T]) RemoveValue(val T) bool {
+	for i := range d.len {
+		if d.buf[d.wrap(d.start+i)] == val {
+			d.Remove(i)
+
+			return true
+		}
+	}
+
+	return false
+}
+
 // Swap exchanges the elements at indices i and j.
 //
 // Panics if either index is invalid (out of range [0, Len()-1]).
@@ -339,6 +505,38 @@ func (d *Deque[T]) Get(idx int) (val T, ok bool) {
 	return d.buf[d.wrap(d.start+idx)], true
 }
 
+// FrontN returns a copy of up to n elements from the front, in front-to-back
+// order, without removing them.
+//
+// Returns all elements if n exceeds Len() and an empty slice if n <= 0.
+// Time complexity: O(n).
+func (d *Deque[T]) FrontN(n int) []T {
+	n = max(0, min(n, d.len))
+
+	vals := make([]T, n)
+	for i := range n {
+		vals[i] = d.buf[d.wrap(d.start+i)]
+	}
+
+	return vals
+}
+
+// BackN returns a copy of up to n elements from the back, in front-to-back
+// order, without removing them.
+//
+// Returns all elements if n exceeds Len() and an empty slice if n <= 0.
+// Time complexity: O(n).
+func (d *Deque[T]) BackN(n int) []T {
+	n = max(0, min(n, d.len))
+
+	vals := make([]T, n)
+	for i := range n {
+		vals[i] = d.buf[d.wrap(d.start+d.len-n+i)]
+	}
+
+	return vals
+}
+
 // Set sets the element at the specified index.
 //
 // Index 0 is the front, Len()-1 is the back. Panics if the index is invalid.
@@ -351,6 +549,25 @@ func (d *Deque[T]) Set(idx int, val T) {
 	d.buf[d.wrap(d.start+idx)] = val
 }
 
+// Apply replaces every element with f(element), in place and front to back.
+//
+// Length and ordering are unchanged. Time complexity: O(n).
+func (d *Deque[T]) Apply(f func(T) T) {
+	for i := range d.len {
+		idx := d.wrap(d.start + i)
+		d.buf[idx] = f(d.buf[idx])
+	}
+}
+
+// Fill sets every current element to val.
+//
+// Length is unchanged. Time complexity: O(n).
+func (d *Deque[T]) Fill(val T) {
+	for i := range d.len {
+		d.buf[d.wrap(d.start+i)] = val
+	}
+}
+
 // IsEmpty checks if the deque has no elements.
 //
 // Time complexity: O(1).
@@ -442,6 +659,47 @@ func (d *Deque[T]) ToSlice() []T {
 	return d.Values()
 }
 
+// Iter returns an iterator over the elements from front to back.
+//
+// Walks the circular buffer in place without materializing a slice.
+// Time complexity: O(n) for full iteration.
+func (d *Deque[T]) Iter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range d.len {
+			if !yield(d.buf[d.wrap(d.start+i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Iter2 returns an iterator over (index, element) pairs from front to back,
+// where index 0 is the front.
+//
+// Time complexity: O(n) for full iteration.
+func (d *Deque[T]) Iter2() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := range d.len {
+			if !yield(i, d.buf[d.wrap(d.start+i)]) {
+				return
+			}
+		}
+	}
+}
+
+// RIter returns an iterator over the elements from back to front.
+//
+// Time complexity: O(n) for full iteration.
+func (d *Deque[T]) RIter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := d.len - 1; i >= 0; i-- {
+			if !yield(d.buf[d.wrap(d.start+i)]) {
+				return
+			}
+		}
+	}
+}
+
 // MarshalJSON serializes the queue's elements into a JSON array in FIFO order.
 //
 // Time complexity: O(n), where n is the number of elements.
@@ -493,6 +751,107 @@ func (d *Deque[T]) String() string {
 	return sb.String()
 }
 
+// DebugString returns the String representation followed by the deque's
+// internal state, for diagnosing wrap-around and growth behavior, e.g.
+//
+//	Deque[a, b, c] (len=3 cap=5 mode=overwrite start=2 end=0)
+//
+// Time complexity: O(n).
+func (d *Deque[T]) DebugString() string {
+	mode := "overwrite"
+	if d.growable {
+		mode = "growable"
+	}
+
+	return fmt.Sprintf("%s (len=%d cap=%d mode=%s start=%d end=%d)",
+		d.String(), d.len, d.capacity, mode, d.start, d.end)
+}
+
+// pushBackN appends n elements to the back, where at(i) returns the i-th
+// one. It implements PushBackSlice and Concat.
+func (d *Deque[T]) pushBackN(n int, at func(int) T) {
+	if n == 0 {
+		return
+	}
+
+	if d.growable {
+		d.reserve(d.len + n)
+	} else if n >= d.capacity {
+		d.reset(func(i int) T { return at(n - d.capacity + i) })
+
+		return
+	}
+
+	for i := range n {
+		d.buf[d.wrap(d.end+i)] = at(i)
+	}
+
+	d.end = d.wrap(d.end + n)
+	d.len += n
+
+	if drop := d.len - d.capacity; drop > 0 {
+		d.start = d.wrap(d.start + drop)
+		d.len -= drop
+	}
+}
+
+// pushFrontN prepends n elements, keeping their order, where at(i) returns
+// the i-th one. It implements PushFrontSlice and ConcatFront.
+func (d *Deque[T]) pushFrontN(n int, at func(int) T) {
+	if n == 0 {
+		return
+	}
+
+	if d.growable {
+		d.reserve(d.len + n)
+	} else if n >= d.capacity {
+		d.reset(at)
+
+		return
+	}
+
+	d.start = d.wrap(d.start - n + d.capacity)
+
+	for i := range n {
+		d.buf[d.wrap(d.start+i)] = at(i)
+	}
+
+	d.len += n
+
+	if drop := d.len - d.capacity; drop > 0 {
+		d.end = d.wrap(d.end - drop + d.capacity)
+		d.len -= drop
+	}
+}
+
+// at returns the i-th element from the front, which must be in range.
+func (d *Deque[T]) at(i int) T {
+	return d.buf[d.wrap(d.start+i)]
+}
+
+// reserve grows the buffer, by repeated growthFactor steps, until it can hold
+// n elements. The buffer is reallocated at most once.
+func (d *Deque[T]) reserve(n int) {
+	c := d.capacity
+	for c < n {
+		c *= growthFactor
+	}
+
+	d.Grow(c)
+}
+
+// reset fills the whole buffer with at(0), ..., at(Capacity()-1), replacing
+// the contents of the deque.
+func (d *Deque[T]) reset(at func(int) T) {
+	for i := range d.capacity {
+		d.buf[i] = at(i)
+	}
+
+	d.start = 0
+	d.end = 0
+	d.len = d.capacity
+}
+
 // next calculates the next index in the circular buffer.
 func (d *Deque[T]) next(idx int) int {
 	return (idx + 1) % d.capacity
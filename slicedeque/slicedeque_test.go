@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/qntx/gods/container"
 	"github.com/qntx/gods/slicedeque"
 )
 
@@ -471,3 +472,534 @@ func TestQueueString(t *testing.T) {
 		t.Errorf("String should start with container name")
 	}
 }
+
+func TestQueueIter(t *testing.T) {
+	t.Parallel()
+
+	queue := slicedeque.New[int](3)
+
+	for range queue.Iter() {
+		t.Errorf("Shouldn't iterate on empty deque")
+	}
+
+	// Wrap the buffer so that start is not at index 0.
+	queue.PushBack(0)
+	queue.PushBack(1)
+	queue.PushBack(2)
+	queue.PushBack(3)
+
+	if actualValue, expectedValue := slices.Collect(queue.Iter()), []int{1, 2, 3}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	if actualValue, expectedValue := slices.Collect(queue.RIter()), []int{3, 2, 1}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	for i, v := range queue.Iter2() {
+		if expectedValue, _ := queue.Get(i); v != expectedValue {
+			t.Errorf("Got %v expected %v at index %v", v, expectedValue, i)
+		}
+	}
+
+	for v := range queue.Iter() {
+		if v == 2 {
+			break
+		}
+	}
+}
+
+func TestQueueEnqueueDequeue(t *testing.T) {
+	t.Parallel()
+
+	var queue container.Queue[int] = slicedeque.NewWith[int](2, true)
+
+	if _, ok := queue.Peek(); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+
+	queue.Enqueue(1)
+	queue.Enqueue(2)
+	queue.Enqueue(3)
+
+	if actualValue, ok := queue.Peek(); actualValue != 1 || !ok {
+		t.Errorf("Got %v,%v expected %v,%v", actualValue, ok, 1, true)
+	}
+
+	for _, expectedValue := range []int{1, 2, 3} {
+		if actualValue, ok := queue.Dequeue(); actualValue != expectedValue || !ok {
+			t.Errorf("Got %v,%v expected %v,%v", actualValue, ok, expectedValue, true)
+		}
+	}
+
+	if _, ok := queue.Dequeue(); ok {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+}
+
+func TestQueueFrontNBackN(t *testing.T) {
+	t.Parallel()
+
+	queue := slicedeque.New[int](3)
+
+	// Wrap the buffer so that start is not at index 0.
+	queue.PushBack(0)
+	queue.PushBack(1)
+	queue.PushBack(2)
+	queue.PushBack(3)
+
+	tests := []struct {
+		n     int
+		front []int
+		back  []int
+	}{
+		{-1, []int{}, []int{}},
+		{0, []int{}, []int{}},
+		{2, []int{1, 2}, []int{2, 3}},
+		{3, []int{1, 2, 3}, []int{1, 2, 3}},
+		{5, []int{1, 2, 3}, []int{1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		if actualValue := queue.FrontN(test.n); !slices.Equal(actualValue, test.front) {
+			t.Errorf("FrontN(%v): got %v expected %v", test.n, actualValue, test.front)
+		}
+
+		if actualValue := queue.BackN(test.n); !slices.Equal(actualValue, test.back) {
+			t.Errorf("BackN(%v): got %v expected %v", test.n, actualValue, test.back)
+		}
+	}
+
+	if actualValue := queue.Len(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+}
+
+func TestQueueTryPush(t *testing.T) {
+	t.Parallel()
+
+	queue := slicedeque.New[int](2)
+
+	if !queue.TryPushBack(1) || !queue.TryPushFront(0) {
+		t.Errorf("TryPush should succeed while the deque has room")
+	}
+
+	if queue.TryPushBack(2) || queue.TryPushFront(-1) {
+		t.Errorf("TryPush should fail on a full deque in overwrite mode")
+	}
+
+	if actualValue, expectedValue := queue.Values(), []int{0, 1}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	growable := slicedeque.NewWith[int](1, true)
+	for i := range 4 {
+		if !growable.TryPushBack(i) {
+			t.Errorf("TryPushBack should always succeed in expansion mode")
+		}
+	}
+
+	if !growable.TryPushFront(-1) {
+		t.Errorf("TryPushFront should always succeed in expansion mode")
+	}
+
+	if actualValue, expectedValue := growable.Values(), []int{-1, 0, 1, 2, 3}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestQueueApplyAndFill(t *testing.T) {
+	t.Parallel()
+
+	queue := slicedeque.New[int](4)
+
+	queue.Apply(func(v int) int { return v + 1 })
+	queue.Fill(1)
+
+	if !queue.IsEmpty() {
+		t.Errorf("Apply/Fill should not add elements to an empty deque")
+	}
+
+	// Wrap the buffer so that the logical range spans the physical end.
+	for i := range 6 {
+		queue.PushBack(i)
+	}
+
+	queue.Apply(func(v int) int { return v * 10 })
+
+	if actualValue, expectedValue := queue.Values(), []int{20, 30, 40, 50}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	queue.PopFront()
+	queue.Fill(7)
+
+	if actualValue, expectedValue := queue.Values(), []int{7, 7, 7}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	queue.PushBack(8)
+
+	if actualValue, expectedValue := queue.Values(), []int{7, 7, 7, 8}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestQueueDebugString(t *testing.T) {
+	t.Parallel()
+
+	queue := slicedeque.New[string](5)
+	for _, v := range []string{"x", "y", "a", "b", "c"} {
+		queue.PushBack(v)
+	}
+
+	queue.PopFront()
+	queue.PopFront()
+
+	if actualValue, expectedValue := queue.DebugString(), "Deque[a, b, c] (len=3 cap=5 mode=overwrite start=2 end=0)"; actualValue != expectedValue {
+		t.Errorf("Got %q expected %q", actualValue, expectedValue)
+	}
+
+	growable := slicedeque.NewWith[int](2, true)
+	growable.PushBack(1)
+
+	if actualValue, expectedValue := growable.DebugString(), "Deque[1] (len=1 cap=2 mode=growable start=0 end=1)"; actualValue != expectedValue {
+		t.Errorf("Got %q expected %q", actualValue, expectedValue)
+	}
+}
+
+func TestQueueInsertFullMatrix(t *testing.T) {
+	t.Parallel()
+
+	for capacity := 1; capacity <= 6; capacity++ {
+		for offset := range capacity {
+			for idx := 0; idx <= capacity; idx++ {
+				for _, growable := range []bool{false, true} {
+					queue := slicedeque.NewWith[int](capacity, growable)
+
+					// Rotate the buffer so that start sits at offset, then fill it.
+					for range offset {
+						queue.PushBack(-1)
+						queue.PopFront()
+					}
+
+					values := make([]int, capacity)
+					for i := range capacity {
+						values[i] = i
+						queue.PushBack(i)
+					}
+
+					queue.Insert(idx, 99)
+
+					expected := slices.Insert(slices.Clone(values), idx, 99)
+
+					switch {
+					case growable:
+					case idx == 0:
+						expected = expected[:capacity] // PushFront drops the back.
+					default:
+						expected = expected[1:] // Otherwise the front is dropped.
+					}
+
+					if actualValue := queue.Values(); !slices.Equal(actualValue, expected) {
+						t.Errorf("cap=%v offset=%v idx=%v growable=%v: got %v expected %v",
+							capacity, offset, idx, growable, actualValue, expected)
+					}
+
+					if actualValue := queue.Len(); actualValue != len(expected) {
+						t.Errorf("cap=%v offset=%v idx=%v growable=%v: got len %v expected %v",
+							capacity, offset, idx, growable, actualValue, len(expected))
+					}
+
+					// The deque must stay consistent for subsequent operations.
+					queue.PushBack(100)
+
+					if actualValue, _ := queue.Back(); actualValue != 100 {
+						t.Errorf("cap=%v offset=%v idx=%v growable=%v: got back %v expected %v",
+							capacity, offset, idx, growable, actualValue, 100)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestQueuePushSlice(t *testing.T) {
+	t.Parallel()
+
+	for capacity := 1; capacity <= 5; capacity++ {
+		for offset := range capacity {
+			for size := 0; size <= capacity; size++ {
+				for k := 0; k <= capacity+2; k++ {
+					for _, growable := range []bool{false, true} {
+						newQueue := func() *slicedeque.Deque[int] {
+							queue := slicedeque.NewWith[int](capacity, growable)
+
+							// Rotate the buffer so that start sits at offset.
+							for range offset {
+								queue.PushBack(-1)
+								queue.PopFront()
+							}
+
+							for i := range size {
+								queue.PushBack(i)
+							}
+
+							return queue
+						}
+
+						vals := make([]int, k)
+						for i := range k {
+							vals[i] = 100 + i
+						}
+
+						back, expectedBack := newQueue(), newQueue()
+						back.PushBackSlice(vals)
+
+						for _, v := range vals {
+							expectedBack.PushBack(v)
+						}
+
+						if actualValue, expectedValue := back.Values(), expectedBack.Values(); !slices.Equal(actualValue, expectedValue) {
+							t.Errorf("PushBackSlice cap=%v offset=%v size=%v k=%v growable=%v: got %v expected %v",
+								capacity, offset, size, k, growable, actualValue, expectedValue)
+						}
+
+						front, expectedFront := newQueue(), newQueue()
+						front.PushFrontSlice(vals)
+
+						for i := len(vals) - 1; i >= 0; i-- {
+							expectedFront.PushFront(vals[i])
+						}
+
+						if actualValue, expectedValue := front.Values(), expectedFront.Values(); !slices.Equal(actualValue, expectedValue) {
+							t.Errorf("PushFrontSlice cap=%v offset=%v size=%v k=%v growable=%v: got %v expected %v",
+								capacity, offset, size, k, growable, actualValue, expectedValue)
+						}
+
+						// Both deques must stay consistent for subsequent operations.
+						for _, queue := range []*slicedeque.Deque[int]{back, front} {
+							queue.PushBack(-2)
+
+							if actualValue, _ := queue.Back(); actualValue != -2 {
+								t.Errorf("cap=%v offset=%v size=%v k=%v growable=%v: got back %v expected %v",
+									capacity, offset, size, k, growable, actualValue, -2)
+							}
+
+							queue.PushFront(-3)
+
+							if actualValue, _ := queue.Front(); actualValue != -3 {
+								t.Errorf("cap=%v offset=%v size=%v k=%v growable=%v: got front %v expected %v",
+									capacity, offset, size, k, growable, actualValue, -3)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	growable := slicedeque.NewWith[int](2, true)
+	growable.PushBackSlice([]int{1, 2, 3, 4, 5})
+
+	if actualValue, expectedValue := growable.Capacity(), 8; actualValue != expectedValue {
+		t.Errorf("Got capacity %v expected %v", actualValue, expectedValue)
+	}
+}
+
+func TestQueueRemoveValue(t *testing.T) {
+	t.Parallel()
+
+	for capacity := 1; capacity <= 6; capacity++ {
+		for offset := range capacity {
+			for target := range capacity {
+				queue := slicedeque.NewWith[int](capacity, true)
+
+				// Rotate the buffer so that start sits at offset, then fill it.
+				for range offset {
+					queue.PushBack(-1)
+					queue.PopFront()
+				}
+
+				values := make([]int, capacity)
+				for i := range capacity {
+					values[i] = i % 3
+					queue.PushBack(i % 3)
+				}
+
+				val := values[target]
+				first := slices.Index(values, val)
+
+				if !queue.RemoveValue(val) {
+					t.Errorf("cap=%v offset=%v val=%v: not found", capacity, offset, val)
+				}
+
+				expected := slices.Delete(slices.Clone(values), first, first+1)
+				if actualValue := queue.Values(); !slices.Equal(actualValue, expected) {
+					t.Errorf("cap=%v offset=%v val=%v: got %v expected %v", capacity, offset, val, actualValue, expected)
+				}
+
+				// The deque must stay consistent for subsequent operations.
+				queue.PushBack(100)
+				queue.PushFront(-100)
+
+				expected = append(append([]int{-100}, expected...), 100)
+				if actualValue := queue.Values(); !slices.Equal(actualValue, expected) {
+					t.Errorf("cap=%v offset=%v val=%v: got %v expected %v", capacity, offset, val, actualValue, expected)
+				}
+			}
+		}
+	}
+
+	queue := slicedeque.NewFrom([]string{"a", "b"}, 2, false)
+	if queue.RemoveValue("z") || queue.Len() != 2 {
+		t.Errorf("RemoveValue should report false and leave the deque unchanged for a missing value")
+	}
+}
+
+func TestQueueInsertWrapMatrix(t *testing.T) {
+	t.Parallel()
+
+	for capacity := 1; capacity <= 7; capacity++ {
+		for offset := range capacity {
+			for size := range capacity {
+				indices := []int{0, 1, size / 2, size - 1, size}
+
+				for _, idx := range indices {
+					if idx < 0 || idx > size {
+						continue
+					}
+
+					for _, growable := range []bool{false, true} {
+						queue := slicedeque.NewWith[int](capacity, growable)
+
+						// Rotate the buffer so that start sits at offset.
+						for range offset {
+							queue.PushBack(-1)
+							queue.PopFront()
+						}
+
+						values := make([]int, size)
+						for i := range size {
+							values[i] = i
+							queue.PushBack(i)
+						}
+
+						queue.Insert(idx, 99)
+
+						expected := slices.Insert(values, idx, 99)
+						if actualValue := queue.Values(); !slices.Equal(actualValue, expected) {
+							t.Errorf("cap=%v offset=%v size=%v idx=%v growable=%v: got %v expected %v",
+								capacity, offset, size, idx, growable, actualValue, expected)
+						}
+
+						// Walking from both ends must agree with Values.
+						reversed := slices.Clone(expected)
+						slices.Reverse(reversed)
+
+						if actualValue := slices.Collect(queue.RIter()); !slices.Equal(actualValue, reversed) {
+							t.Errorf("cap=%v offset=%v size=%v idx=%v growable=%v: got reversed %v",
+								capacity, offset, size, idx, growable, actualValue)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestQueueCollect(t *testing.T) {
+	t.Parallel()
+
+	values := []int{1, 2, 3, 4, 5}
+
+	queue := slicedeque.Collect(slices.Values(values), 3, false)
+	if actualValue, expectedValue := queue.Values(), []int{3, 4, 5}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	queue = slicedeque.Collect(slices.Values(values), 2, true)
+	if actualValue, expectedValue := queue.Values(), values; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	queue = slicedeque.Collect(slices.Values([]int{}), 4, false)
+	if actualValue := queue.Len(); actualValue != 0 {
+		t.Errorf("Got %v expected %v", actualValue, 0)
+	}
+
+	if actualValue := queue.Capacity(); actualValue != 4 {
+		t.Errorf("Got %v expected %v", actualValue, 4)
+	}
+}
+
+func TestQueueConcat(t *testing.T) {
+	t.Parallel()
+
+	// newQueue returns a deque holding size elements starting at base, with
+	// its buffer rotated so that start sits at offset.
+	newQueue := func(capacity, offset, size, base int, growable bool) *slicedeque.Deque[int] {
+		queue := slicedeque.NewWith[int](capacity, growable)
+
+		for range offset {
+			queue.PushBack(-1)
+			queue.PopFront()
+		}
+
+		for i := range size {
+			queue.PushBack(base + i)
+		}
+
+		return queue
+	}
+
+	for capacity := 1; capacity <= 4; capacity++ {
+		for offset := range capacity {
+			for size := 0; size <= capacity; size++ {
+				for otherSize := 0; otherSize <= 5; otherSize++ {
+					for _, growable := range []bool{false, true} {
+						other := newQueue(max(otherSize, 1), otherSize/2, otherSize, 100, false)
+						otherValues := other.Values()
+
+						back, expectedBack := newQueue(capacity, offset, size, 0, growable), newQueue(capacity, offset, size, 0, growable)
+						back.Concat(other)
+						expectedBack.PushBackSlice(otherValues)
+
+						if actualValue, expectedValue := back.Values(), expectedBack.Values(); !slices.Equal(actualValue, expectedValue) {
+							t.Errorf("Concat cap=%d off=%d size=%d other=%d growable=%v: got %v expected %v",
+								capacity, offset, size, otherSize, growable, actualValue, expectedValue)
+						}
+
+						front, expectedFront := newQueue(capacity, offset, size, 0, growable), newQueue(capacity, offset, size, 0, growable)
+						front.ConcatFront(other)
+						expectedFront.PushFrontSlice(otherValues)
+
+						if actualValue, expectedValue := front.Values(), expectedFront.Values(); !slices.Equal(actualValue, expectedValue) {
+							t.Errorf("ConcatFront cap=%d off=%d size=%d other=%d growable=%v: got %v expected %v",
+								capacity, offset, size, otherSize, growable, actualValue, expectedValue)
+						}
+
+						if actualValue := other.Values(); !slices.Equal(actualValue, otherValues) {
+							t.Errorf("Concat modified other: got %v expected %v", actualValue, otherValues)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// A deque may be concatenated with itself.
+	queue := newQueue(3, 2, 3, 1, false)
+	queue.Concat(queue)
+
+	if actualValue, expectedValue := queue.Values(), []int{1, 2, 3}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+
+	queue = newQueue(2, 1, 2, 1, true)
+	queue.ConcatFront(queue)
+
+	if actualValue, expectedValue := queue.Values(), []int{1, 2, 1, 2}; !slices.Equal(actualValue, expectedValue) {
+		t.Errorf("Got %v expected %v", actualValue, expectedValue)
+	}
+}